@@ -61,6 +61,88 @@ func (r *List) Execute(args []string) error {
 	return cmds.List()
 }
 
+type Items struct {
+	Tag  string `short:"t" long:"tag" description:"Only list items with this tag or category"`
+	View string `short:"V" long:"view" description:"Only list items in this saved view"`
+}
+
+func (r *Items) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	return cmds.Items(r.Tag, r.View)
+}
+
+type Later struct {
+	Add    int `short:"a" long:"add" description:"Add the item with this ID to the end of the queue"`
+	Remove int `short:"d" long:"remove" description:"Remove the item with this ID from the queue"`
+	Up     int `long:"up" description:"Move the item with this ID one place up the queue"`
+	Down   int `long:"down" description:"Move the item with this ID one place down the queue"`
+}
+
+func (r *Later) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case r.Add != 0:
+		return cmds.AddToReadLater(r.Add)
+	case r.Remove != 0:
+		return cmds.RemoveFromReadLater(r.Remove)
+	case r.Up != 0:
+		return cmds.MoveReadLater(r.Up, -1)
+	case r.Down != 0:
+		return cmds.MoveReadLater(r.Down, 1)
+	}
+
+	return cmds.ReadLater()
+}
+
+type Export struct{}
+
+func (r *Export) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	return cmds.ExportFavourites()
+}
+
+type Rules struct {
+	Test RulesTest `command:"test" description:"Show which rules match an item"`
+}
+
+type RulesTest struct {
+	Positional struct {
+		ID int `positional-arg-name:"ID" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func (r *RulesTest) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	return cmds.TestRules(r.Positional.ID)
+}
+
+type Keys struct{}
+
+func (r *Keys) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	return cmds.ShowKeys()
+}
+
 type Version struct{}
 
 func (r *Version) Execute(args []string) error {
@@ -114,6 +196,37 @@ func (r *Import) Execute(args []string) error {
 	return nil
 }
 
+type PreviewScrape struct {
+	Item       string `long:"item" description:"CSS selector matching each item"`
+	Title      string `long:"title" description:"CSS selector for the item title"`
+	Link       string `long:"link" description:"CSS selector for the item link"`
+	Date       string `long:"date" description:"CSS selector for the item date"`
+	DateFormat string `long:"date-format" description:"Go time layout used to parse the date"`
+	Content    string `long:"content" description:"CSS selector for the item content"`
+	Positional struct {
+		Url string `positional-arg-name:"URL" required:"yes"`
+	} `positional-args:"yes"`
+}
+
+func (r *PreviewScrape) Execute(args []string) error {
+	cmds, err := getCmds()
+	if err != nil {
+		return err
+	}
+
+	return cmds.PreviewScrape(config.Feed{
+		URL: r.Positional.Url,
+		Scrape: &config.ScrapeOptions{
+			Item:       r.Item,
+			Title:      r.Title,
+			Link:       r.Link,
+			Date:       r.Date,
+			DateFormat: r.DateFormat,
+			Content:    r.Content,
+		},
+	})
+}
+
 func getCmds() (*commands.Commands, error) {
 	cfg, err := config.New(options.ConfigPath, options.Pager, options.PreviewFeeds, version)
 	if err != nil {
@@ -128,6 +241,10 @@ func getCmds() (*commands.Commands, error) {
 	if err != nil {
 		return nil, fmt.Errorf("main.go: %w", err)
 	}
+	if err = commands.ApplyKeys(cfg.Keys); err != nil {
+		return nil, err
+	}
+
 	cmds := commands.New(cfg, s)
 	return cmds, nil
 }
@@ -141,10 +258,16 @@ func main() {
 	parser.AddCommand("add", "Add feed", "Add a new feed", &Add{})
 	parser.AddCommand("config", "Show config", "Show configuration", &Config{})
 	parser.AddCommand("list", "List feeds", "List all feeds", &List{})
+	parser.AddCommand("items", "List items", "List items with their IDs", &Items{})
+	parser.AddCommand("export", "Export favourites", "Print favourites and their notes as markdown", &Export{})
+	parser.AddCommand("later", "Read later", "List or manage the read later queue", &Later{})
+	parser.AddCommand("rules", "Rules", "Check the rules applied to fetched items", &Rules{})
+	parser.AddCommand("keys", "Show keys", "Show the key bindings, including any remapped in the config", &Keys{})
 	parser.AddCommand("version", "Show Version", "Display version information", &Version{})
 	parser.AddCommand("refresh", "Refresh feeds", "refresh feed(s) without opening TUI", &Refresh{})
 	parser.AddCommand("unread", "Count unread", "Get count of unread items", &Unread{})
 	parser.AddCommand("import", "Import feeds", "Import feeds from an OMPL file", &Import{})
+	parser.AddCommand("preview-scrape", "Preview scrape", "Test the CSS selectors of a scrape feed", &PreviewScrape{})
 
 	// parse the command line arguments
 	_, err := parser.Parse()
@@ -0,0 +1,72 @@
+package commands
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// osc52 is the escape sequence that sets the terminal's clipboard
+func osc52(text string) string {
+	return "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+}
+
+// tmuxPassthrough wraps a sequence for tmux to pass on to the outer terminal
+// untouched, for when it doesn't handle OSC 52 itself
+func tmuxPassthrough(seq string) string {
+	return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+}
+
+// clipboardCmd is the local clipboard tool, if there is one to fall back to
+// for terminals without OSC 52
+func clipboardCmd() *exec.Cmd {
+	var tools [][]string
+	switch {
+	case runtime.GOOS == "darwin":
+		tools = [][]string{{"pbcopy"}}
+	case IsWayland():
+		tools = [][]string{{"wl-copy"}}
+	case os.Getenv("DISPLAY") != "":
+		tools = [][]string{{"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}}
+	}
+
+	for _, t := range tools {
+		if _, err := exec.LookPath(t[0]); err == nil {
+			return exec.Command(t[0], t[1:]...)
+		}
+	}
+
+	return nil
+}
+
+// copyToClipboard sets the terminal's clipboard with OSC 52, so it works over
+// ssh and in tmux, along with the local clipboard when that is turned on and
+// there is a tool for it, then shows status
+func (c Commands) copyToClipboard(text string, status string) tea.Cmd {
+	return func() tea.Msg {
+		seq := osc52(text)
+		if os.Getenv("TMUX") != "" {
+			seq += tmuxPassthrough(seq)
+		}
+		fmt.Fprint(c.out, seq)
+
+		if !c.config.LocalClipboard {
+			return statusUpdate{status: status}
+		}
+
+		if cmd := clipboardCmd(); cmd != nil {
+			cmd.Stdin = strings.NewReader(text)
+			if err := cmd.Run(); err != nil {
+				log.Println("copyToClipboard:", err)
+			}
+		}
+
+		return statusUpdate{status: status}
+	}
+}
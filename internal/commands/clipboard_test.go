@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestCopyToClipboard(t *testing.T) {
+	t.Setenv("TMUX", "")
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	test.HandleError(t, err)
+	defer f.Close()
+
+	c := Commands{config: &config.Config{}, out: newTerminal(f)}
+	msg := c.copyToClipboard("https://example.com", "Copied")()
+	test.Equal(t, "Copied", msg.(statusUpdate).status, "status")
+
+	out, err := os.ReadFile(f.Name())
+	test.HandleError(t, err)
+	test.Equal(t, osc52("https://example.com"), string(out), "sent to the program's output")
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"os/exec"
+	"path"
 	"regexp"
 	"slices"
 	"strings"
@@ -29,10 +30,14 @@ import (
 type Commands struct {
 	config *config.Config
 	store  store.Store
+	// images are drawn in articles when set
+	images *imageCache
+	// out is where the TUI draws, and sequences for the terminal are sent
+	out *terminal
 }
 
 func New(config *config.Config, store store.Store) *Commands {
-	return &Commands{config, store}
+	return &Commands{config: config, store: store, out: newTerminal(os.Stdout)}
 }
 
 func convertItems(its []store.Item) []list.Item {
@@ -91,6 +96,59 @@ func (c Commands) List() error {
 	return outputToPager(output)
 }
 
+// Items prints items with their IDs. With a tag, every item with that tag
+// is listed regardless of read state, and with a view only the items in
+// that saved search are.
+func (c Commands) Items(tag string, view string) error {
+	var (
+		its []store.Item
+		err error
+	)
+
+	if view != "" {
+		if _, ok := c.config.GetView(view); !ok {
+			return fmt.Errorf("commands Items: no view named %q", view)
+		}
+		c.config.ActiveView = view
+	}
+
+	if tag != "" {
+		its, err = c.store.GetItemsByTag(tag, c.config.Ordering)
+	} else {
+		its, err = c.GetAllFeeds()
+	}
+	if err != nil {
+		return fmt.Errorf("commands Items: %w", err)
+	}
+
+	if v, ok := c.config.GetView(view); ok && tag != "" {
+		its, err = applyView(its, v, time.Now())
+		if err != nil {
+			return fmt.Errorf("commands Items: %w", err)
+		}
+	}
+
+	output := ""
+
+	for _, item := range its {
+		output += fmt.Sprintf("%d. %s \n  - %s\n", item.ID, item.Title, item.Link)
+		if tags := slices.Concat(item.Categories, item.Tags); len(tags) > 0 {
+			output += fmt.Sprintf("  - %s\n", formatTags(tags))
+		}
+	}
+
+	if c.config.Pager == "false" {
+		fmt.Println(output)
+		return nil
+	}
+
+	return outputToPager(output)
+}
+
+func formatTags(tags []string) string {
+	return "#" + strings.Join(tags, " #")
+}
+
 func (c Commands) Add(url string, name string) error {
 	err := c.config.AddFeed(config.Feed{URL: url, Name: name})
 	if err != nil {
@@ -109,6 +167,39 @@ func (c Commands) Refresh() error {
 	return nil
 }
 
+// PreviewScrape fetches a scrape feed and prints the items its selectors
+// match, so selectors can be tuned without touching the store. If url
+// matches a configured scrape feed its selectors are used as defaults.
+func (c Commands) PreviewScrape(feed config.Feed) error {
+	for _, f := range c.config.Feeds {
+		if f.URL == feed.URL && f.Scrape != nil && feed.Scrape.Item == "" {
+			feed.Scrape = f.Scrape
+		}
+	}
+	feed.Type = config.FeedTypeScrape
+
+	r, err := rss.Fetch(feed, c.config.HTTPOptions, c.config.Version, c.config.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("commands PreviewScrape: %w", err)
+	}
+
+	output := fmt.Sprintf("%s (%d items)\n\n", r.Channel.Title, len(r.Channel.Items))
+
+	for _, item := range r.Channel.Items {
+		output += fmt.Sprintf("%s \n  - %s\n", item.Title, item.Link)
+		if !item.PubDate.IsZero() {
+			output += fmt.Sprintf("  - %s\n", item.PubDate)
+		}
+	}
+
+	if c.config.Pager == "false" {
+		fmt.Println(output)
+		return nil
+	}
+
+	return outputToPager(output)
+}
+
 func (c Commands) ShowConfig() error {
 	yaml, err := yaml.Marshal(&c.config)
 	if err != nil {
@@ -142,19 +233,7 @@ func (c Commands) ImportFeeds(source string) error {
 	if err != nil {
 		return fmt.Errorf("config.ImportFeeds: error parsing OPML: %w", err)
 	}
-	feeds := make([]config.Feed, 0)
-	for _, outline := range opml.Body.Outlines {
-		if outline.XMLUrl == nil {
-			log.Printf("config.ImportFeeds: No url for outline %s\n", outline.Title)
-		} else {
-			feeds = append(feeds, config.Feed{
-				Name: outline.Title,
-				URL:  outline.XMLUrl.String(),
-			})
-		}
-
-		feeds = slices.Concat(feeds, getChildFeeds(outline))
-	}
+	feeds := getChildFeeds(Outline{Outlines: opml.Body.Outlines}, "")
 
 	errors := 0
 	for _, feed := range feeds {
@@ -170,19 +249,31 @@ func (c Commands) ImportFeeds(source string) error {
 	return nil
 }
 
-func getChildFeeds(outline Outline) []config.Feed {
+// getChildFeeds collects the feeds under an outline. Outlines without a
+// url are folders, and become the group of the feeds inside them.
+func getChildFeeds(outline Outline, group string) []config.Feed {
 	feeds := make([]config.Feed, 0)
 	for _, child := range outline.Outlines {
 		if child.XMLUrl == nil {
-			log.Printf("getChildFeeds: No url for outline %s\n", child.Title)
-		} else {
-			feeds = append(feeds, config.Feed{
-				Name: child.Title,
-				URL:  child.XMLUrl.String(),
-			})
+			name := child.Title
+			if name == "" {
+				name = child.Text
+			}
+			if len(child.Outlines) == 0 {
+				log.Printf("getChildFeeds: No url for outline %s\n", name)
+			}
+
+			feeds = slices.Concat(feeds, getChildFeeds(child, path.Join(group, name)))
+			continue
 		}
 
-		feeds = slices.Concat(feeds, getChildFeeds(child))
+		feeds = append(feeds, config.Feed{
+			Name:  child.Title,
+			URL:   child.XMLUrl.String(),
+			Group: group,
+		})
+
+		feeds = slices.Concat(feeds, getChildFeeds(child, group))
 	}
 
 	return feeds
@@ -212,12 +303,19 @@ func (c Commands) fetchAllFeeds() ([]store.Item, []ErrorItem, error) {
 		return items, errorItems, fmt.Errorf("no feeds found, add to nom/config.yml")
 	}
 
+	// compiled before fetching, as returning early would leave the fetches
+	// blocked sending their results
+	rules, err := compileRules(c.config.Rules)
+	if err != nil {
+		return items, errorItems, fmt.Errorf("fetchAllFeeds: %w", err)
+	}
+
 	ch := make(chan FetchResultError)
 
 	for _, feed := range feeds {
 		wg.Add(1)
 
-		go fetchFeed(ch, &wg, feed, c.config.HTTPOptions, c.config.Version)
+		go fetchFeed(ch, &wg, feed, c.config.HTTPOptions, c.config.Version, c.config.ConfigDir)
 	}
 
 	go func() {
@@ -225,8 +323,22 @@ func (c Commands) fetchAllFeeds() ([]store.Item, []ErrorItem, error) {
 		close(ch)
 	}()
 
-	err := c.store.BeginBatch()
+	// rule actions other than delete are applied once the batch is
+	// committed, and only to items that are new
+	type ruledItem struct {
+		id    int
+		item  store.Item
+		rules []rule
+	}
+	var ruled []ruledItem
+
+	err = c.store.BeginBatch()
 	if err != nil {
+		// let the fetches finish
+		go func() {
+			for range ch {
+			}
+		}()
 		return items, errorItems, fmt.Errorf("fetchAllFeeds: failed to begin batch: %w", err)
 	}
 	defer c.store.EndBatch()
@@ -246,24 +358,75 @@ func (c Commands) fetchAllFeeds() ([]store.Item, []ErrorItem, error) {
 				Link:        r.Link,
 				PublishedAt: r.PubDate,
 				Title:       r.Title,
+				Enclosures:  toStoreEnclosures(r.Enclosures),
+				Categories:  r.Categories,
+				ITunes:      store.ITunes(r.ITunes),
+			}
+
+			ms := matchingRules(rules, i)
+			preview := includes(c.config.PreviewFeeds, config.Feed{URL: result.url})
+
+			if deletes(ms) {
+				if !preview {
+					err := c.store.DeleteItem(i)
+					if err != nil {
+						log.Fatalf("[commands.go] fetchAllFeeds: %e", err)
+					}
+				}
+				continue
 			}
 
 			// only store if non-preview feed
-			if !includes(c.config.PreviewFeeds, config.Feed{URL: result.url}) {
-				err := c.store.UpsertItem(i)
+			if !preview {
+				id, created, err := c.store.UpsertItem(i)
 				if err != nil {
 					log.Fatalf("[commands.go] fetchAllFeeds: %e", err)
 					continue
 				}
+
+				if created && len(ms) > 0 {
+					ruled = append(ruled, ruledItem{id: id, item: i, rules: ms})
+				}
 			}
 
 			items = append(items, i)
 		}
 	}
 
+	// full text is fetched once the new items are committed
+	err = c.store.EndBatch()
+	if err != nil {
+		return items, errorItems, fmt.Errorf("fetchAllFeeds: %w", err)
+	}
+
+	for _, r := range ruled {
+		if err := c.applyRules(r.id, r.item, r.rules); err != nil {
+			log.Println("fetchAllFeeds:", err)
+		}
+	}
+
+	if err := c.rescore(); err != nil {
+		log.Println("fetchAllFeeds:", err)
+	}
+
+	if err := c.clusterDuplicates(); err != nil {
+		log.Println("fetchAllFeeds:", err)
+	}
+
+	c.fetchFullTexts()
+	c.fetchGemtexts()
+
 	return items, errorItems, nil
 }
 
+func toStoreEnclosures(es []rss.Enclosure) []store.Enclosure {
+	var enclosures []store.Enclosure
+	for _, e := range es {
+		enclosures = append(enclosures, store.Enclosure(e))
+	}
+	return enclosures
+}
+
 func includes[T comparable](arr []T, item T) bool {
 	for _, v := range arr {
 		if v == item {
@@ -319,13 +482,13 @@ func (c Commands) GetGlamourisedArticle(ID int) (string, error) {
 	}
 
 	if c.config.AutoRead && !article.Read() {
-		err = c.store.ToggleRead(article.ID)
+		err = c.toggleRead(article.ID)
 		if err != nil {
 			return "", fmt.Errorf("[commands.go] GetGlamourisedArticle: %w", err)
 		}
 	}
 
-	content, err := glamouriseItem(article, c.config.Theme)
+	content, err := glamouriseItem(article, c.config.Theme, 0, c.images)
 	if err != nil {
 		return "", fmt.Errorf("[commands.go] GetGlamourisedArticle: %w", err)
 	}
@@ -333,6 +496,39 @@ func (c Commands) GetGlamourisedArticle(ID int) (string, error) {
 	return content, nil
 }
 
+// renderArticle renders an open article again as it stands, so unlike
+// GetGlamourisedArticle it isn't marked read
+func (c Commands) renderArticle(ID int) (string, error) {
+	article, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return "", fmt.Errorf("[commands.go] renderArticle: %w", err)
+	}
+
+	content, err := glamouriseItem(article, c.config.Theme, 0, c.images)
+	if err != nil {
+		return "", fmt.Errorf("[commands.go] renderArticle: %w", err)
+	}
+
+	return content, nil
+}
+
+// GetPreviewArticle renders an article wrapped to width for the preview pane.
+// It is only glanced at there, so unlike GetGlamourisedArticle it isn't
+// marked read.
+func (c Commands) GetPreviewArticle(ID int, width int) (string, error) {
+	article, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return "", fmt.Errorf("[commands.go] GetPreviewArticle: %w", err)
+	}
+
+	content, err := glamouriseItem(article, c.config.Theme, width, nil)
+	if err != nil {
+		return "", fmt.Errorf("[commands.go] GetPreviewArticle: %w", err)
+	}
+
+	return content, nil
+}
+
 func getStyleConfigWithOverrides(theme config.Theme) (sc ansi.StyleConfig) {
 	switch theme.Glamour {
 	case "light":
@@ -355,7 +551,10 @@ func getStyleConfigWithOverrides(theme config.Theme) (sc ansi.StyleConfig) {
 	return sc
 }
 
-func glamouriseItem(item store.Item, theme config.Theme) (string, error) {
+// glamouriseItem renders an item as markdown, wrapped to width or glamour's
+// default when it is 0, with the images in it that are loaded when images
+// is set
+func glamouriseItem(item store.Item, theme config.Theme, width int, images *imageCache) (string, error) {
 	var mdown string
 
 	title := item.Title
@@ -370,20 +569,53 @@ func glamouriseItem(item store.Item, theme config.Theme) (string, error) {
 		mdown += "\n"
 		mdown += item.PublishedAt.String()
 	}
+	if tags := slices.Concat(item.Categories, item.Tags); len(tags) > 0 {
+		mdown += "\n"
+		mdown += formatTags(tags)
+	}
+	if episode := formatEpisode(item.ITunes); episode != "" {
+		mdown += "\n"
+		mdown += episode
+	}
 	mdown += "\n\n"
 	mdown += item.Link
 	mdown += "\n\n"
-	mdown += htmlToMd(item.Content)
+	if len(item.Enclosures) > 0 {
+		mdown += formatEnclosures(item.Enclosures)
+		mdown += "\n\n"
+	}
+	body, links := footnoteLinks(articleMarkdown(item), item.Link)
+	if images != nil {
+		body = images.mark(body, links)
+	}
+	mdown += body
+	if len(links) > 0 {
+		mdown += "\n\n"
+		mdown += formatLinks(links)
+	}
+	if item.Note != "" {
+		mdown += "\n\n## Notes\n\n"
+		mdown += item.Note
+	}
 
-	r, _ := glamour.NewTermRenderer(
+	opts := []glamour.TermRendererOption{
 		glamour.WithStyles(getStyleConfigWithOverrides(theme)),
-	)
+	}
+	if width > 0 {
+		opts = append(opts, glamour.WithWordWrap(width))
+	}
+
+	r, _ := glamour.NewTermRenderer(opts...)
 
 	out, err := r.Render(mdown)
 	if err != nil {
 		return "", fmt.Errorf("GlamouriseItem: %w", err)
 	}
 
+	if images != nil {
+		out = images.place(out, links)
+	}
+
 	return out, nil
 }
 
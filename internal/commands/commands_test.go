@@ -0,0 +1,155 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFetchMailReusedSubject(t *testing.T) {
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	feed := config.Feed{URL: "../test/data/newsletters.mbox", Type: config.FeedTypeMbox}
+	c := New(&config.Config{ConfigDir: t.TempDir(), Feeds: []config.Feed{feed}}, s)
+
+	// fetched twice, as on every refresh
+	for i := 0; i < 2; i++ {
+		_, errs, err := c.fetchAllFeeds()
+		test.HandleError(t, err)
+		test.Equal(t, 0, len(errs), "feed errors")
+	}
+
+	items, err := s.GetAllItems("")
+	test.HandleError(t, err)
+	test.Equal(t, 3, len(items), "messages kept apart")
+
+	resent := 0
+	for _, it := range items {
+		if it.Title == "Issue 41" {
+			resent++
+		}
+	}
+	test.Equal(t, 2, resent, "same subject kept twice")
+}
+
+func TestDeleteRuleReusedSubject(t *testing.T) {
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	feed := config.Feed{URL: "../test/data/newsletters.mbox", Type: config.FeedTypeMbox}
+	cfg := &config.Config{ConfigDir: t.TempDir(), Feeds: []config.Feed{feed}}
+	c := New(cfg, s)
+
+	_, _, err = c.fetchAllFeeds()
+	test.HandleError(t, err)
+
+	// only the resend matches, though the first issue has its subject
+	cfg.Rules = []config.Rule{{Name: "resends", Match: config.RuleMatch{Content: "links fixed"}, Actions: config.RuleActions{Delete: true}}}
+	_, _, err = c.fetchAllFeeds()
+	test.HandleError(t, err)
+
+	items, err := s.GetAllItems("")
+	test.HandleError(t, err)
+	test.Equal(t, 2, len(items), "only the matching message deleted")
+	for _, it := range items {
+		test.Equal(t, true, it.Link != "mid:issue41-resend@weekly.example", "resend deleted")
+	}
+}
+
+func TestDeleteRuleKeepsNotesAndReadLater(t *testing.T) {
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	feed := config.Feed{URL: "../test/data/newsletters.mbox", Type: config.FeedTypeMbox}
+	cfg := &config.Config{ConfigDir: t.TempDir(), Feeds: []config.Feed{feed}}
+	c := New(cfg, s)
+
+	items, _, err := c.fetchAllFeeds()
+	test.HandleError(t, err)
+	test.Equal(t, 3, len(items), "messages fetched")
+
+	stored, err := s.GetAllItems("")
+	test.HandleError(t, err)
+	var noted, queued int
+	for _, it := range stored {
+		switch it.Title {
+		case "Issue 43":
+			queued = it.ID
+		case "Issue 41":
+			noted = it.ID
+		}
+	}
+	test.HandleError(t, s.SetNote(noted, "Keep this one."))
+	test.HandleError(t, s.AddToReadLater(queued))
+
+	cfg.Rules = []config.Rule{{Name: "everything", Match: config.RuleMatch{Title: "."}, Actions: config.RuleActions{Delete: true}}}
+	_, _, err = c.fetchAllFeeds()
+	test.HandleError(t, err)
+
+	stored, err = s.GetAllItems("")
+	test.HandleError(t, err)
+	test.Equal(t, 2, len(stored), "only the plain item deleted")
+
+	item, err := s.GetItemByID(noted)
+	test.HandleError(t, err)
+	test.Equal(t, "Keep this one.", item.Note, "note kept")
+
+	item, err = s.GetItemByID(queued)
+	test.HandleError(t, err)
+	test.Equal(t, true, item.ReadLater, "still saved for later")
+}
+
+func TestFetchInvalidRule(t *testing.T) {
+	feed := config.Feed{URL: "../test/data/newsletters.mbox", Type: config.FeedTypeMbox}
+	cfg := &config.Config{
+		Feeds: []config.Feed{feed},
+		Rules: []config.Rule{{Name: "broken", Match: config.RuleMatch{Title: "("}}},
+	}
+	c := New(cfg, nil)
+
+	before := runtime.NumGoroutine()
+	_, _, err := c.fetchAllFeeds()
+	if err == nil {
+		t.Fatal("expected an invalid rule error")
+	}
+
+	// a fetch left sending its result would still be running
+	time.Sleep(50 * time.Millisecond)
+	test.Equal(t, true, runtime.NumGoroutine() <= before, "fetches left running")
+}
+
+func TestFetchFullTextsBatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html><body><article><p>The whole article, long enough to be taken as the content of the page.</p></article></body></html>")
+	}))
+	defer srv.Close()
+
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	feed := config.Feed{URL: "https://example.com/feed", FullText: true}
+	c := New(&config.Config{Feeds: []config.Feed{feed}}, s)
+
+	for i := 0; i < fullTextBatch+5; i++ {
+		_, _, err := s.UpsertItem(store.Item{FeedURL: feed.URL, Title: fmt.Sprint(i), Link: fmt.Sprintf("%s/%d", srv.URL, i)})
+		test.HandleError(t, err)
+	}
+
+	c.fetchFullTexts()
+	left, err := s.GetItemsWithoutFullText(feed.URL)
+	test.HandleError(t, err)
+	test.Equal(t, 5, len(left), "rest left for the next refresh")
+
+	c.fetchFullTexts()
+	left, err = s.GetItemsWithoutFullText(feed.URL)
+	test.HandleError(t, err)
+	test.Equal(t, 0, len(left), "fetched on the next refresh")
+}
@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/guyfedwards/nom/v2/internal/dedupe"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// clusterDuplicates finds the same story across feeds and stores which
+// items are copies of each other
+func (c Commands) clusterDuplicates() error {
+	if !c.config.Dedupe.Enabled {
+		return nil
+	}
+
+	items, err := c.store.GetAllItems(c.config.Ordering)
+	if err != nil {
+		return fmt.Errorf("clusterDuplicates: %w", err)
+	}
+
+	threshold := c.config.Dedupe.Threshold
+	if threshold == 0 {
+		threshold = dedupe.DefaultThreshold
+	}
+
+	ds := make([]dedupe.Item, 0, len(items))
+	for _, item := range items {
+		ds = append(ds, dedupe.Item{ID: item.ID, Feed: item.FeedURL, Link: item.Link, Title: item.Title})
+	}
+
+	err = c.store.SetClusters(dedupe.Cluster(ds, threshold))
+	if err != nil {
+		return fmt.Errorf("clusterDuplicates: %w", err)
+	}
+
+	return nil
+}
+
+// collapseClusters keeps the first item of each cluster, noting the feeds
+// of the copies it stands in for
+func collapseClusters(items []store.Item) []store.Item {
+	first := make(map[int]int)
+	var is []store.Item
+
+	for _, item := range items {
+		if item.Cluster == 0 {
+			is = append(is, item)
+			continue
+		}
+
+		i, ok := first[item.Cluster]
+		if !ok {
+			first[item.Cluster] = len(is)
+			is = append(is, item)
+			continue
+		}
+
+		name := item.FeedName
+		if name == "" {
+			name = item.FeedURL
+		}
+		if name != is[i].FeedName && !slices.Contains(is[i].AlsoIn, name) {
+			is[i].AlsoIn = append(is[i].AlsoIn, name)
+		}
+	}
+
+	return is
+}
+
+// toggleRead toggles an item read, along with its copies from other feeds
+func (c Commands) toggleRead(ID int) error {
+	err := c.store.ToggleRead(ID)
+	if err != nil {
+		return fmt.Errorf("toggleRead: %w", err)
+	}
+
+	if !c.config.Dedupe.Enabled {
+		return nil
+	}
+
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("toggleRead: %w", err)
+	}
+
+	if item.Cluster != 0 {
+		err = c.store.SetClusterRead(item.Cluster, item.Read())
+		if err != nil {
+			return fmt.Errorf("toggleRead: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// setRead marks items read or unread, along with their copies from other feeds
+func (c Commands) setRead(IDs []int, read bool) error {
+	if c.config.Dedupe.Enabled {
+		items, err := c.store.GetAllItems(c.config.Ordering)
+		if err != nil {
+			return fmt.Errorf("setRead: %w", err)
+		}
+
+		ids := make(map[int]bool, len(IDs))
+		for _, id := range IDs {
+			ids[id] = true
+		}
+
+		clusters := make(map[int]bool)
+		for _, item := range items {
+			if item.Cluster != 0 && ids[item.ID] {
+				clusters[item.Cluster] = true
+			}
+		}
+		for _, item := range items {
+			if clusters[item.Cluster] && !ids[item.ID] {
+				IDs = append(IDs, item.ID)
+			}
+		}
+	}
+
+	err := c.store.SetRead(IDs, read)
+	if err != nil {
+		return fmt.Errorf("setRead: %w", err)
+	}
+
+	return nil
+}
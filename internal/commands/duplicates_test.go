@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestCollapseClusters(t *testing.T) {
+	items := []store.Item{
+		{ID: 3, FeedName: "hn", Title: "Go 1.22 is released", Cluster: 1},
+		{ID: 2, FeedName: "lobsters", Title: "Weekly links"},
+		{ID: 1, FeedName: "go blog", Title: "Go 1.22 is released!", Cluster: 1},
+		{ID: 4, FeedURL: "https://reddit.com/r/golang.rss", Title: "Go 1.22 released", Cluster: 1},
+		{ID: 5, FeedName: "hn", Title: "Go 1.22 is out", Cluster: 1},
+	}
+
+	is := collapseClusters(items)
+
+	var got []string
+	for _, i := range is {
+		got = append(got, fmt.Sprintf("%d %s", i.ID, strings.Join(i.AlsoIn, ", ")))
+	}
+
+	test.Equal(t, "3 go blog, https://reddit.com/r/golang.rss|2 ", strings.Join(got, "|"), "the first copy should stand in for the cluster")
+}
@@ -3,6 +3,7 @@ package commands
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/guyfedwards/nom/v2/internal/config"
 	"github.com/guyfedwards/nom/v2/internal/rss"
@@ -46,31 +47,92 @@ func (c Commands) GetAllFeeds() ([]store.Item, error) {
 		return []store.Item{}, fmt.Errorf("[commands.go] GetAllFeeds: %w", err)
 	}
 
-	is, err := c.store.GetAllItems(c.config.Ordering)
-	if err != nil {
-		return []store.Item{}, fmt.Errorf("commands.go: GetAllFeeds %w", err)
-	}
+	view, hasView := c.config.GetView(c.config.ActiveView)
 
-	if c.config.ShowFavourites {
-		is = onlyFavourites(is)
-	} else if c.config.ShowRead {
-		is = showRead(is)
+	var is []store.Item
+	if c.config.ShowReadLater {
+		// the queue has its own order and keeps items once read
+		is, err = c.store.GetReadLaterItems()
+		if err != nil {
+			return []store.Item{}, fmt.Errorf("commands.go: GetAllFeeds %w", err)
+		}
 	} else {
-		is = defaultView(is)
+		is, err = c.store.GetAllItems(c.config.Ordering)
+		if err != nil {
+			return []store.Item{}, fmt.Errorf("commands.go: GetAllFeeds %w", err)
+		}
+
+		// views without a read state follow the show read toggle
+		if !hasView || view.Read == nil {
+			is = c.toggledView(is)
+		}
 	}
 
-	// add FeedName from config for custom names
+	// add FeedName from config for custom names. Mail feeds keep the stored
+	// sender unless they have been given a name.
 	for i := 0; i < len(is); i++ {
 		for _, f := range c.config.Feeds {
-			if f.URL == is[i].FeedURL {
+			if f.URL == is[i].FeedURL && (!f.IsMail() || f.Name != "") {
 				is[i].FeedName = f.Name
 			}
 		}
 	}
 
+	// views match on the configured feed names, so are applied last
+	if hasView && !c.config.ShowReadLater {
+		is, err = applyView(is, view, time.Now())
+		if err != nil {
+			return []store.Item{}, fmt.Errorf("commands.go: GetAllFeeds %w", err)
+		}
+	}
+
+	if !c.config.ShowReadLater {
+		is = c.inSidebarSelection(is)
+	}
+
+	if c.config.Dedupe.Enabled && !c.config.ShowReadLater {
+		is = collapseClusters(is)
+	}
+
 	return is, nil
 }
 
+// inSidebarSelection keeps the items of the feed or group picked in the
+// sidebar
+func (c Commands) inSidebarSelection(items []store.Item) []store.Item {
+	if c.config.ActiveFeed == "" && c.config.ActiveGroup == "" {
+		return items
+	}
+
+	feeds := make(map[string]bool)
+	for _, f := range c.config.GetFeeds() {
+		if c.config.ActiveFeed != "" {
+			feeds[f.URL] = f.URL == c.config.ActiveFeed
+		} else {
+			feeds[f.URL] = f.InGroup(c.config.ActiveGroup)
+		}
+	}
+
+	var is []store.Item
+	for _, item := range items {
+		if feeds[item.FeedURL] {
+			is = append(is, item)
+		}
+	}
+
+	return is
+}
+
+func (c Commands) toggledView(is []store.Item) []store.Item {
+	if c.config.ShowFavourites {
+		return onlyFavourites(is)
+	} else if c.config.ShowRead {
+		return showRead(is)
+	}
+
+	return defaultView(is)
+}
+
 func onlyFavourites(items []store.Item) (is []store.Item) {
 	for _, v := range items {
 		if v.Favourite {
@@ -96,10 +158,10 @@ func defaultView(items []store.Item) (is []store.Item) {
 	return is
 }
 
-func fetchFeed(ch chan FetchResultError, wg *sync.WaitGroup, feed config.Feed, httpOpts *config.HTTPOptions, version string) {
+func fetchFeed(ch chan FetchResultError, wg *sync.WaitGroup, feed config.Feed, httpOpts *config.HTTPOptions, version string, configDir string) {
 	defer wg.Done()
 
-	r, err := rss.Fetch(feed, httpOpts, version)
+	r, err := rss.Fetch(feed, httpOpts, version, configDir)
 
 	if err != nil {
 		ch <- FetchResultError{res: rss.RSS{}, err: err, url: feed.URL}
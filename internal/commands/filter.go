@@ -1,162 +1,121 @@
 package commands
 
 import (
-	"fmt"
-	"regexp"
-	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/sahilm/fuzzy"
 
 	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/query"
 )
 
 // Struct to aid in filtering items into ranks for BubbleTea
 type Filterer struct {
-	FeedNames []string
-	Term      struct {
-		Title     string
-		FeedNames []string
-	}
+	Query  *query.Query
+	Err    error
 	Config config.FilterConfig
+	// the items being filtered by ID, as TUIItem.FilterValue() only holds
+	// the title, feed name and tags
+	items map[int]TUIItem
 }
 
-// Filters by feednames
-func (f *Filterer) FilterByFeedName(filterValues []string, targetFilterValues []string) fuzzy.Matches {
-	// find matching feeds and keep the best matching one in case there are multiple
-	ranksGrouped := map[int]fuzzy.Match{}
-	for _, feedName := range filterValues {
-		matches := fuzzy.Find(feedName, targetFilterValues)
-		for _, m := range matches {
-			prevMatch, ok := ranksGrouped[m.Index]
-			if !ok {
-				ranksGrouped[m.Index] = m
-			} else {
-				if prevMatch.Score < m.Score {
-					ranksGrouped[m.Index] = m
-				}
-			}
-		}
-	}
-
-	var ranks fuzzy.Matches
-	for _, m := range ranksGrouped {
-		ranks = append(ranks, m)
-	}
-
-	// keep the same order as the input
-	// this keeps the same order of items in the UI and prevents the items from being shuffled
-	slices.SortStableFunc(ranks, func(left fuzzy.Match, right fuzzy.Match) int {
-		return right.Index - left.Index
-	})
-
-	return ranks
-}
-
-// Breaks what's returned from TUIItem.FilterValue() into a TUIItem.
+// Breaks what's returned from TUIItem.FilterValue() into a TUIItem, using
+// the whole item when it is known.
 func (f *Filterer) GetItem(filterValue string) TUIItem {
 	var i TUIItem
 
 	splits := strings.Split(filterValue, "||")
 
-	i.Title = splits[0]
-	i.FeedName = strings.ToLower(splits[1])
+	i.ID, _ = strconv.Atoi(splits[0])
+	if item, ok := f.items[i.ID]; ok {
+		return item
+	}
+
+	if len(splits) > 1 {
+		i.Title = splits[1]
+	}
+	if len(splits) > 2 {
+		i.FeedName = splits[2]
+	}
+	// categories and user tags are matched alike
+	i.Tags = splits[min(len(splits), 3):]
 
 	return i
 }
 
-// Extracts `tag:.*` from the stored f.Term.Title
-func (f *Filterer) ExtractFiltersFor(tags ...string) []string {
-	var extractedTags []string
-	done := false
-	for !done {
-		// `complete` matches 3 potential capture groups after tags, in which
-		// `[^"]` matches a character that isn't a `"`, `[^']` that isn't a `'`,
-		// etc. If it's no quotes, you can also do `feed:with\ spaces`
-		// `incomplete` matches unfinished quoted tags and removes them from the
-		// search. The order of the capture groups MATTERS.
-		// In both examples, the %s section matches all potential tag aliases
-		// passed in for one tag.
-		complete := regexp.MustCompile(fmt.Sprintf(`(%s):("([^"]+)"|'([^']+)'|(([^\\ ]|\\ )+))`, strings.Join(tags, "|")))
-		incomplete := regexp.MustCompile(fmt.Sprintf(`(%s):("[^"]*|'[^']*)`, strings.Join(tags, "|")))
-
-		matches := complete.FindStringSubmatch(f.Term.Title)
-
-		match := ""
-		if matches != nil {
-			// double quotes
-			if matches[3] != "" {
-				match = matches[3]
-				// single quotes
-			} else if matches[4] != "" {
-				match = matches[4]
-				// no quotes
-			} else if matches[5] != "" {
-				match = strings.ReplaceAll(matches[5], `\ `, " ")
-			}
-			f.Term.Title = strings.Replace(f.Term.Title, matches[0], "", 1)
-		} else {
-			// fallback to regular matching without filter
-			matches = incomplete.FindStringSubmatch(f.Term.Title)
-			if matches != nil {
-				f.Term.Title = strings.Replace(f.Term.Title, matches[0], "", 1)
-			}
-			done = true
-		}
-
-		if match != "" {
-			extractedTags = append(extractedTags, strings.ToLower(match))
-		}
-	}
-	if f.Term.Title == "" {
-		f.Term.Title = " "
+func toQueryItem(i TUIItem) query.Item {
+	return query.Item{
+		Title:     i.Title,
+		FeedName:  i.FeedName,
+		Author:    i.Author,
+		Content:   i.Content,
+		Tags:      append(append([]string{}, i.Categories...), i.Tags...),
+		Read:      i.Read,
+		Favourite: i.Favourite,
+		Published: i.PublishedAt,
 	}
-
-	return extractedTags
 }
 
-// Runs all filters
+// Runs the query. Plain text is ranked fuzzily on the title, anything else
+// keeps the items that match in their original order.
 func (f *Filterer) Filter(targets []string) []fuzzy.Match {
-	var targetTitles []string
-	var targetFeedNames []string
-
-	for _, target := range targets {
-		i := f.GetItem(target)
-		title := i.Title
-		if f.Config.DefaultIncludeFeedName {
-			title = strings.Join([]string{i.FeedName, i.Title}, " ")
+	if f.Err != nil {
+		return nil
+	}
+
+	if text, ok := f.Query.Text(); ok {
+		var targetTitles []string
+		for _, target := range targets {
+			i := f.GetItem(target)
+
+			title := i.Title
+			if f.Config.DefaultIncludeFeedName {
+				title = strings.Join([]string{i.FeedName, i.Title}, " ")
+			}
+			targetTitles = append(targetTitles, title)
 		}
-		targetTitles = append(targetTitles, title)
-		targetFeedNames = append(targetFeedNames, i.FeedName)
+
+		ranks := fuzzy.Find(text, targetTitles)
+		sort.Stable(ranks)
+
+		return ranks
 	}
 
 	var ranks fuzzy.Matches
-	if len(f.FeedNames) == 0 {
-		ranks = fuzzy.Find(f.Term.Title, targetTitles)
-	} else {
-		ranks = f.FilterByFeedName(f.FeedNames, targetFeedNames)
+	for index, target := range targets {
+		if f.Query.Match(toQueryItem(f.GetItem(target))) {
+			ranks = append(ranks, fuzzy.Match{Index: index})
+		}
 	}
 
-	sort.Stable(ranks)
-
 	return ranks
 }
 
-func NewFilterer(term string, config config.FilterConfig) Filterer {
+func NewFilterer(term string, config config.FilterConfig, items map[int]TUIItem) Filterer {
 	var f Filterer
 
 	f.Config = config
-	f.Term.Title = term
-	f.FeedNames = f.ExtractFiltersFor("feedname", "feed", "f")
+	f.Query, f.Err = query.Parse(term, query.Options{IncludeFeedName: config.DefaultIncludeFeedName})
+	f.items = items
 
 	return f
 }
 
-func CustomFilter(config config.FilterConfig) list.FilterFunc {
+// CustomFilter filters items with the query language, items should be the
+// ones the list is showing.
+func CustomFilter(config config.FilterConfig, items []list.Item) list.FilterFunc {
+	byID := make(map[int]TUIItem, len(items))
+	for _, it := range items {
+		if i, ok := it.(TUIItem); ok {
+			byID[i.ID] = i
+		}
+	}
+
 	return func(term string, targets []string) []list.Rank {
-		filterer := NewFilterer(term, config)
+		filterer := NewFilterer(term, config, byID)
 
 		ranks := filterer.Filter(targets)
 
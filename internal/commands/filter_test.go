@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+var filterTargets = []string{
+	TUIItem{Title: "Go 1.22 released", FeedName: "golang", Categories: []string{"Go", "Releases"}}.FilterValue(),
+	TUIItem{Title: "Rust 1.75 released", FeedName: "rust", Categories: []string{"Releases"}}.FilterValue(),
+	TUIItem{Title: "Why I write Go", FeedName: "blog"}.FilterValue(),
+}
+
+func TestFilterByTag(t *testing.T) {
+	ranks := CustomFilter(config.FilterConfig{}, nil)("tag:releases", filterTargets)
+
+	test.Equal(t, 2, len(ranks), "wrong number of tagged items")
+	test.Equal(t, 0, ranks[0].Index, "tagged items should keep their order")
+	test.Equal(t, 1, ranks[1].Index, "tagged items should keep their order")
+}
+
+func TestFilterByTagAndTitle(t *testing.T) {
+	ranks := CustomFilter(config.FilterConfig{}, nil)("category:go released", filterTargets)
+
+	test.Equal(t, 1, len(ranks), "wrong number of matches")
+	test.Equal(t, 0, ranks[0].Index, "index should refer to the unfiltered targets")
+}
+
+func TestFilterByTagAndFeed(t *testing.T) {
+	ranks := CustomFilter(config.FilterConfig{}, nil)("tag:releases f:rust", filterTargets)
+
+	test.Equal(t, 1, len(ranks), "wrong number of matches")
+	test.Equal(t, 1, ranks[0].Index, "wrong item matched")
+}
+
+func TestFilterUsesWholeItems(t *testing.T) {
+	items := []list.Item{
+		TUIItem{ID: 1, Title: "Go 1.22 released", FeedName: "golang", Author: "The Go Team", Read: true},
+		TUIItem{ID: 2, Title: "Why I write Go", FeedName: "blog", Author: "Alice", Content: "<p>Generics</p>"},
+	}
+	var targets []string
+	for _, it := range items {
+		targets = append(targets, it.FilterValue())
+	}
+
+	filter := CustomFilter(config.FilterConfig{}, items)
+
+	ranks := filter("is:unread", targets)
+	test.Equal(t, 1, len(ranks), "wrong number of unread items")
+	test.Equal(t, 1, ranks[0].Index, "wrong unread item")
+
+	ranks = filter("author:team OR content:generics", targets)
+	test.Equal(t, 2, len(ranks), "wrong number of matches")
+	test.Equal(t, 0, ranks[0].Index, "matches should keep their order")
+
+	ranks = filter("(is:unread", targets)
+	test.Equal(t, 0, len(ranks), "invalid queries match nothing")
+}
+
+func TestFilterPlainTextIsFuzzy(t *testing.T) {
+	ranks := CustomFilter(config.FilterConfig{}, nil)("wrt go", filterTargets)
+
+	test.Equal(t, 1, len(ranks), "wrong number of matches")
+	test.Equal(t, 2, ranks[0].Index, "wrong item matched")
+}
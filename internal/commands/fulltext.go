@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/rss"
+)
+
+const (
+	// how many articles are fetched at once for fulltext feeds
+	fullTextConcurrency = 4
+	// how many articles are fetched per feed on each refresh, so adding a
+	// feed with a long history doesn't hold up the first one
+	fullTextBatch = 20
+)
+
+type fullTextFetched struct {
+	err error
+}
+
+// storeFullText fetches and stores the article an item links to
+func (c Commands) storeFullText(ID int) error {
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("storeFullText: %w", err)
+	}
+
+	if !isWebLink(item.Link) {
+		return fmt.Errorf("storeFullText: %q is not a web page", item.Link)
+	}
+
+	content, err := rss.FetchArticle(item.Link, c.config.HTTPOptions, c.config.Version)
+	if err != nil {
+		return fmt.Errorf("storeFullText: %w", err)
+	}
+
+	return c.store.SetFullText(ID, content)
+}
+
+func isWebLink(link string) bool {
+	return strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://")
+}
+
+// fetchFullTexts stores the full text of new items in feeds with fulltext
+// set, newest first, leaving any over fullTextBatch for later refreshes.
+// Items that fail are stored with empty text so they aren't retried on every
+// refresh, and keep showing the feed's content.
+func (c Commands) fetchFullTexts() {
+	var ids []int
+	for _, f := range c.config.Feeds {
+		if !f.FullText {
+			continue
+		}
+
+		items, err := c.store.GetItemsWithoutFullText(f.URL)
+		if err != nil {
+			continue
+		}
+		for _, it := range items[:min(len(items), fullTextBatch)] {
+			ids = append(ids, it.ID)
+		}
+	}
+
+	for _, id := range fetchEach(ids, fullTextConcurrency, c.storeFullText) {
+		c.store.SetFullText(id, "")
+	}
+}
+
+// fetchEach runs fetch for each of ids, concurrency at a time, returning
+// the IDs it failed for
+func fetchEach(ids []int, concurrency int, fetch func(ID int) error) []int {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed []int
+	)
+	sem := make(chan struct{}, concurrency)
+
+	for _, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := fetch(id); err != nil {
+				mu.Lock()
+				failed = append(failed, id)
+				mu.Unlock()
+			}
+		}(id)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// FetchFullText fetches the full text of the open article in the background
+func (m model) FetchFullText(ID int) tea.Cmd {
+	c := m.commands
+	return func() tea.Msg {
+		return fullTextFetched{err: c.storeFullText(ID)}
+	}
+}
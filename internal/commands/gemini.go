@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/guyfedwards/nom/v2/internal/rss"
+)
+
+// how many entries are fetched at once for gemini feeds
+const gemtextConcurrency = 4
+
+// storeGemtext fetches and stores the gemtext an item from a gemini feed
+// links to
+func (c Commands) storeGemtext(ID int) error {
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("storeGemtext: %w", err)
+	}
+
+	if !rss.IsGemini(item.Link) {
+		return fmt.Errorf("storeGemtext: %q is not on gemini", item.Link)
+	}
+
+	gemtext, err := rss.FetchGemtext(item.Link, c.config.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("storeGemtext: %w", err)
+	}
+
+	return c.store.SetGemtext(ID, gemtext)
+}
+
+// fetchGemtexts stores the gemtext of new items in gemini feeds. Items that
+// fail, or link off gemini, are stored with empty gemtext so they aren't
+// retried on every refresh, and keep showing the feed's content.
+func (c Commands) fetchGemtexts() {
+	var ids []int
+	for _, f := range c.config.Feeds {
+		if !rss.IsGemini(f.URL) {
+			continue
+		}
+
+		items, err := c.store.GetItemsWithoutGemtext(f.URL)
+		if err != nil {
+			continue
+		}
+		for _, it := range items {
+			ids = append(ids, it.ID)
+		}
+	}
+
+	for _, id := range fetchEach(ids, gemtextConcurrency, c.storeGemtext) {
+		c.store.SetGemtext(id, "")
+	}
+}
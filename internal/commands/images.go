@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/rss"
+	"github.com/guyfedwards/nom/v2/internal/termimg"
+)
+
+const (
+	// how many images are downloaded at once
+	imageConcurrency = 4
+	// glamour wraps at 80 columns, less its margins
+	maxImageCols     = 76
+	defaultImageRows = 20
+	// images with more pixels than this aren't decoded, as a small file can
+	// claim dimensions that take gigabytes to hold
+	maxImagePixels = 40_000_000
+	// images not drawn for this long are removed from the cache
+	imageCacheAge = 30 * 24 * time.Hour
+)
+
+// imageCaptionRe finds the text footnoteLinks leaves for an image
+var imageCaptionRe = regexp.MustCompile(`\[image(?:: [^\]]*)?\] \[(\d+)\]`)
+
+type imagesFetched struct {
+	id int
+	// drawn is how many of the article's images can be drawn
+	drawn int
+}
+
+// imageCache keeps article images on disk, and in memory ready to draw once
+// they have been loaded
+type imageCache struct {
+	dir      string
+	protocol termimg.Protocol
+	opts     termimg.Options
+
+	mu sync.Mutex
+	// placed are the images loaded, with no rows for those that failed
+	placed map[string]termimg.Placement
+}
+
+func newImageCache(cfg *config.Config) (*imageCache, error) {
+	protocol, err := termimg.Parse(cfg.Images.Protocol, os.Getenv)
+	if err != nil {
+		return nil, fmt.Errorf("newImageCache: %w", err)
+	}
+
+	rows := cfg.Images.MaxHeight
+	if rows == 0 {
+		rows = defaultImageRows
+	}
+
+	dir := cfg.GetImageCacheDir()
+	go pruneImages(dir, time.Now().Add(-imageCacheAge))
+
+	return &imageCache{
+		dir:      dir,
+		protocol: protocol,
+		opts: termimg.Options{
+			MaxCols: maxImageCols,
+			MaxRows: min(rows, termimg.MaxRows),
+			Cell:    termimg.CellSize(),
+		},
+		placed: make(map[string]termimg.Placement),
+	}, nil
+}
+
+// pruneImages removes the images in the cache last drawn before cutoff
+func pruneImages(dir string, cutoff time.Time) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		os.Remove(filepath.Join(dir, e.Name()))
+	}
+}
+
+// path is where an image is kept on disk
+func (ic *imageCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(ic.dir, hex.EncodeToString(sum[:]))
+}
+
+func (ic *imageCache) placement(url string) (termimg.Placement, bool) {
+	ic.mu.Lock()
+	defer ic.mu.Unlock()
+
+	p, ok := ic.placed[url]
+	return p, ok && len(p.Rows) > 0
+}
+
+// load gets an image ready to draw, downloading it unless it's on disk
+func (ic *imageCache) load(url string, cfg *config.Config) (termimg.Placement, error) {
+	ic.mu.Lock()
+	p, ok := ic.placed[url]
+	ic.mu.Unlock()
+	if ok {
+		return p, nil
+	}
+
+	p, err := ic.loadFile(url, cfg)
+
+	ic.mu.Lock()
+	ic.placed[url] = p
+	ic.mu.Unlock()
+
+	return p, err
+}
+
+func (ic *imageCache) loadFile(url string, cfg *config.Config) (termimg.Placement, error) {
+	path := ic.path(url)
+
+	data, err := os.ReadFile(path)
+	if err == nil {
+		// kept from pruning while it's still read
+		now := time.Now()
+		os.Chtimes(path, now, now)
+	} else {
+		data, err = rss.FetchImage(url, cfg.HTTPOptions, cfg.Version)
+		if err != nil {
+			return termimg.Placement{}, fmt.Errorf("imageCache.load: %w", err)
+		}
+
+		// written under a temporary name so a partial file is never read
+		if err := os.MkdirAll(ic.dir, 0755); err != nil {
+			return termimg.Placement{}, fmt.Errorf("imageCache.load: %w", err)
+		}
+		if err := os.WriteFile(path+".part", data, 0644); err != nil {
+			return termimg.Placement{}, fmt.Errorf("imageCache.load: %w", err)
+		}
+		if err := os.Rename(path+".part", path); err != nil {
+			return termimg.Placement{}, fmt.Errorf("imageCache.load: %w", err)
+		}
+	}
+
+	conf, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return termimg.Placement{}, fmt.Errorf("imageCache.load: %s: %w", url, err)
+	}
+	if conf.Width*conf.Height > maxImagePixels {
+		return termimg.Placement{}, fmt.Errorf("imageCache.load: %s: %dx%d is too big", url, conf.Width, conf.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return termimg.Placement{}, fmt.Errorf("imageCache.load: %s: %w", url, err)
+	}
+
+	opts := ic.opts
+	opts.ID = imageID(url)
+
+	p, err := termimg.Render(img, ic.protocol, opts)
+	if err != nil {
+		return termimg.Placement{}, fmt.Errorf("imageCache.load: %w", err)
+	}
+
+	return p, nil
+}
+
+// imageID tells images apart on screen, as kitty takes 24 bit ids in the
+// colour of its placeholders
+func imageID(url string) uint32 {
+	return max(crc32.ChecksumIEEE([]byte(url))&0xffffff, 1)
+}
+
+// imageMarker is the paragraph an image is drawn in place of
+const imageMarker = "NOMIMAGE"
+
+// markImages puts a paragraph marking where each image that can be drawn
+// goes, before the line with its caption. Code blocks are left alone.
+func markImages(mdown string, links []articleLink, drawn func(articleLink) bool) string {
+	marked := make(map[int]bool)
+
+	lines := strings.Split(mdown, "\n")
+	code := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			code = !code
+			continue
+		}
+		if code {
+			continue
+		}
+
+		var markers []string
+		for _, m := range imageCaptionRe.FindAllStringSubmatch(line, -1) {
+			n, _ := strconv.Atoi(m[1])
+			if n < 1 || n > len(links) || marked[n] || !drawn(links[n-1]) {
+				continue
+			}
+
+			marked[n] = true
+			markers = append(markers, imageMarker+strconv.Itoa(n)+"\n\n")
+		}
+
+		lines[i] = strings.Join(markers, "") + line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// placeImages swaps the rendered markers for the rows of their images, at
+// the marker's indent
+func placeImages(content string, rows func(n int) []string) string {
+	var out []string
+	for _, line := range strings.Split(content, "\n") {
+		plain := plainLine(line)
+		n, ok := strings.CutPrefix(strings.TrimSpace(plain), imageMarker)
+		if !ok {
+			out = append(out, line)
+			continue
+		}
+
+		i, err := strconv.Atoi(n)
+		if err != nil {
+			out = append(out, line)
+			continue
+		}
+
+		indent := strings.Repeat(" ", len(plain)-len(strings.TrimLeft(plain, " ")))
+		for _, row := range rows(i) {
+			out = append(out, indent+row)
+		}
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// mark marks where the images that are loaded go in an article
+func (ic *imageCache) mark(mdown string, links []articleLink) string {
+	return markImages(mdown, links, func(l articleLink) bool {
+		_, ok := ic.placement(l.url)
+		return l.image && ok
+	})
+}
+
+// place draws the marked images in the rendered article
+func (ic *imageCache) place(content string, links []articleLink) string {
+	return placeImages(content, func(n int) []string {
+		if n < 1 || n > len(links) {
+			return nil
+		}
+		p, _ := ic.placement(links[n-1].url)
+		return p.Rows
+	})
+}
+
+// fetchImages loads the images of an article in the background, then has it
+// drawn again with them. Kitty is sent the images as they load, to draw
+// later.
+func (m model) fetchImages(ID int) tea.Cmd {
+	c := m.commands
+	if c.images == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		links, err := c.articleLinks(ID)
+		if err != nil {
+			return statusUpdate{status: err.Error()}
+		}
+
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			drawn int
+			out   strings.Builder
+		)
+		sem := make(chan struct{}, imageConcurrency)
+
+		for _, l := range links {
+			if !l.image {
+				continue
+			}
+
+			wg.Add(1)
+			sem <- struct{}{}
+
+			go func(url string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				// images that fail keep their caption
+				p, err := c.images.load(url, c.config)
+				if err != nil || len(p.Rows) == 0 {
+					return
+				}
+
+				mu.Lock()
+				drawn++
+				out.WriteString(p.Transmit)
+				mu.Unlock()
+			}(l.url)
+		}
+		wg.Wait()
+
+		if out.Len() > 0 {
+			fmt.Fprint(c.out, out.String())
+		}
+
+		return imagesFetched{id: ID, drawn: drawn}
+	}
+}
@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"bytes"
+	"image"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+
+	"github.com/guyfedwards/nom/v2/internal/termimg"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestMarkImages(t *testing.T) {
+	mdown := "Intro [image: a cat] [1]\n\n" +
+		"```\n[image] [2]\n```\n\n" +
+		"[image] [2] and again [image: a cat] [1]\n\n" +
+		"[image: not loaded] [3]"
+	links := []articleLink{
+		{n: 1, text: "a cat", url: "https://example.com/cat.png", image: true},
+		{n: 2, url: "https://example.com/dog.png", image: true},
+		{n: 3, text: "not loaded", url: "https://example.com/bird.png", image: true},
+	}
+
+	out := markImages(mdown, links, func(l articleLink) bool { return l.n != 3 })
+
+	want := "NOMIMAGE1\n\nIntro [image: a cat] [1]\n\n" +
+		"```\n[image] [2]\n```\n\n" +
+		"NOMIMAGE2\n\n[image] [2] and again [image: a cat] [1]\n\n" +
+		"[image: not loaded] [3]"
+	test.Equal(t, want, out, "images should be marked once, before their caption")
+}
+
+func TestPlaceImages(t *testing.T) {
+	content := strings.Join([]string{
+		"  Intro",
+		"  \x1b[38;5;252mNOMIMAGE1\x1b[0m   ",
+		"  [image] [1]",
+	}, "\n")
+
+	out := placeImages(content, func(n int) []string {
+		return []string{"row 1", "row 2"}
+	})
+
+	want := strings.Join([]string{
+		"  Intro",
+		"  row 1",
+		"  row 2",
+		"  [image] [1]",
+	}, "\n")
+	test.Equal(t, want, out, "markers should be swapped for the image at their indent")
+}
+
+func TestLoadImageTooBig(t *testing.T) {
+	ic := &imageCache{dir: t.TempDir(), placed: make(map[string]termimg.Placement)}
+	url := "https://example.com/huge.gif"
+
+	var buf bytes.Buffer
+	test.HandleError(t, gif.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1)), nil))
+	// the logical screen claims 60000x60000
+	data := buf.Bytes()
+	copy(data[6:10], []byte{0x60, 0xea, 0x60, 0xea})
+	test.HandleError(t, os.WriteFile(ic.path(url), data, 0644))
+
+	_, err := ic.loadFile(url, &config.Config{})
+	test.Equal(t, true, err != nil && strings.Contains(err.Error(), "too big"), "huge image refused before decoding")
+}
+
+func TestPruneImages(t *testing.T) {
+	dir := t.TempDir()
+	old, recent := filepath.Join(dir, "old"), filepath.Join(dir, "recent")
+	test.HandleError(t, os.WriteFile(old, nil, 0644))
+	test.HandleError(t, os.WriteFile(recent, nil, 0644))
+	month := time.Now().Add(-imageCacheAge - time.Hour)
+	test.HandleError(t, os.Chtimes(old, month, month))
+
+	pruneImages(dir, time.Now().Add(-imageCacheAge))
+
+	_, err := os.Stat(old)
+	test.Equal(t, true, os.IsNotExist(err), "old image removed")
+	_, err = os.Stat(recent)
+	test.HandleError(t, err)
+}
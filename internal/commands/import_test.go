@@ -71,3 +71,23 @@ func TestOMPLParser(t *testing.T) {
 		}
 	}
 }
+
+func TestOPMLGroups(t *testing.T) {
+	bytes, err := os.ReadFile(opmlFixture)
+	if err != nil {
+		t.Fatalf("unable to read opml fixture file: %s", err)
+	}
+	result, err := parseOPML(bytes)
+	test.HandleError(t, err)
+
+	fs := getChildFeeds(Outline{Outlines: result.Body.Outlines}, "")
+	test.Equal(t, 20, len(fs), "missing feeds")
+	for _, f := range fs {
+		switch f.Name {
+		case "Formula 1":
+			test.Equal(t, "Sports", f.Group, "wrong group for "+f.Name)
+		case "Gizmodo":
+			test.Equal(t, "Tech", f.Group, "wrong group for "+f.Name)
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// itemState is what the journal can put back on an item
+type itemState struct {
+	readAt    time.Time
+	favourite bool
+	tags      []string
+}
+
+func (s itemState) equal(o itemState) bool {
+	return s.readAt.Equal(o.readAt) && s.favourite == o.favourite && slices.Equal(s.tags, o.tags)
+}
+
+// change is one action in the journal, with the items it changed as they
+// were before and after
+type change struct {
+	name   string
+	before map[int]itemState
+	after  map[int]itemState
+}
+
+// journal records read, favourite and tag changes made in the TUI so they
+// can be undone and redone, for as long as it is open
+type journal struct {
+	done   []change
+	undone []change
+}
+
+// itemStates are the states of items, and of their copies in other feeds,
+// or of every item for nil IDs
+func (c Commands) itemStates(IDs []int) (map[int]itemState, error) {
+	items, err := c.store.GetItemStates(IDs)
+	if err != nil {
+		return nil, fmt.Errorf("itemStates: %w", err)
+	}
+
+	states := make(map[int]itemState, len(items))
+	for _, item := range items {
+		tags := slices.Clone(item.Tags)
+		slices.Sort(tags)
+		states[item.ID] = itemState{readAt: item.ReadAt, favourite: item.Favourite, tags: tags}
+	}
+
+	return states, nil
+}
+
+// diffStates keeps only the items that changed, leaving out any added or
+// removed in between
+func diffStates(name string, before, after map[int]itemState) change {
+	ch := change{name: name, before: make(map[int]itemState), after: make(map[int]itemState)}
+	for id, a := range after {
+		if b, ok := before[id]; ok && !b.equal(a) {
+			ch.before[id] = b
+			ch.after[id] = a
+		}
+	}
+
+	return ch
+}
+
+// record runs fn and journals the items it changed among IDs and their
+// copies in other feeds. nil IDs looks at every item, for actions such as
+// mark all read.
+func (j *journal) record(c *Commands, name string, IDs []int, fn func() error) error {
+	before, err := c.itemStates(IDs)
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	after, err := c.itemStates(IDs)
+	if err != nil {
+		return fmt.Errorf("journal: %w", err)
+	}
+
+	ch := diffStates(name, before, after)
+	if len(ch.after) == 0 {
+		return nil
+	}
+
+	j.done = append(j.done, ch)
+	j.undone = nil
+
+	return nil
+}
+
+// undo puts back the items changed by the last action, returning its name,
+// or "" if there is nothing to undo
+func (j *journal) undo(c *Commands) (string, error) {
+	if len(j.done) == 0 {
+		return "", nil
+	}
+
+	ch := j.done[len(j.done)-1]
+	if err := c.restoreStates(ch.before); err != nil {
+		return "", fmt.Errorf("journal undo: %w", err)
+	}
+
+	j.done = j.done[:len(j.done)-1]
+	j.undone = append(j.undone, ch)
+
+	return ch.name, nil
+}
+
+// redo makes the last undone action again, returning its name, or "" if
+// there is nothing to redo
+func (j *journal) redo(c *Commands) (string, error) {
+	if len(j.undone) == 0 {
+		return "", nil
+	}
+
+	ch := j.undone[len(j.undone)-1]
+	if err := c.restoreStates(ch.after); err != nil {
+		return "", fmt.Errorf("journal redo: %w", err)
+	}
+
+	j.undone = j.undone[:len(j.undone)-1]
+	j.done = append(j.done, ch)
+
+	return ch.name, nil
+}
+
+// restoreStates sets items back to the states given
+func (c Commands) restoreStates(states map[int]itemState) error {
+	IDs := make([]int, 0, len(states))
+	for id := range states {
+		IDs = append(IDs, id)
+	}
+
+	current, err := c.itemStates(IDs)
+	if err != nil {
+		return fmt.Errorf("restoreStates: %w", err)
+	}
+
+	readAt := make(map[int]time.Time)
+	var favourite, unfavourite []int
+	addTags := make(map[string][]int)
+	removeTags := make(map[string][]int)
+
+	for id, s := range states {
+		cur, ok := current[id]
+		if !ok {
+			// removed since, by a refresh
+			continue
+		}
+
+		if !cur.readAt.Equal(s.readAt) {
+			readAt[id] = s.readAt
+		}
+
+		if cur.favourite != s.favourite {
+			if s.favourite {
+				favourite = append(favourite, id)
+			} else {
+				unfavourite = append(unfavourite, id)
+			}
+		}
+
+		for _, t := range s.tags {
+			if !slices.Contains(cur.tags, t) {
+				addTags[t] = append(addTags[t], id)
+			}
+		}
+		for _, t := range cur.tags {
+			if !slices.Contains(s.tags, t) {
+				removeTags[t] = append(removeTags[t], id)
+			}
+		}
+	}
+
+	if err := c.store.SetReadAt(readAt); err != nil {
+		return fmt.Errorf("restoreStates: %w", err)
+	}
+	if err := c.store.SetFavourite(favourite, true); err != nil {
+		return fmt.Errorf("restoreStates: %w", err)
+	}
+	if err := c.store.SetFavourite(unfavourite, false); err != nil {
+		return fmt.Errorf("restoreStates: %w", err)
+	}
+	for t, ids := range addTags {
+		if err := c.store.SetTag(ids, t, true); err != nil {
+			return fmt.Errorf("restoreStates: %w", err)
+		}
+	}
+	for t, ids := range removeTags {
+		if err := c.store.SetTag(ids, t, false); err != nil {
+			return fmt.Errorf("restoreStates: %w", err)
+		}
+	}
+
+	// tags can change the score
+	if len(addTags) > 0 || len(removeTags) > 0 {
+		if err := c.rescore(); err != nil {
+			return fmt.Errorf("restoreStates: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (m *model) record(name string, IDs []int, fn func() error) error {
+	return m.journal.record(m.commands, name, IDs, fn)
+}
+
+// openArticle renders an article to read, journalling it being marked read
+// when autoread is on
+func (m *model) openArticle(ID int) (string, error) {
+	if !m.commands.config.AutoRead {
+		return m.commands.GetGlamourisedArticle(ID)
+	}
+
+	var content string
+	err := m.record("read", []int{ID}, func() error {
+		var err error
+		content, err = m.commands.GetGlamourisedArticle(ID)
+		return err
+	})
+
+	return content, err
+}
+
+// undo undoes the last change, or redoes the last undone one, returning a
+// status to show
+func (m *model) undo(redo bool) string {
+	var (
+		name string
+		err  error
+	)
+	if redo {
+		name, err = m.journal.redo(m.commands)
+	} else {
+		name, err = m.journal.undo(m.commands)
+	}
+
+	switch {
+	case err != nil:
+		return err.Error()
+	case name == "" && redo:
+		return "Nothing to redo."
+	case name == "":
+		return "Nothing to undo."
+	case redo:
+		return "Redid " + name + "."
+	default:
+		return "Undid " + name + "."
+	}
+}
+
+// updateListKeepingCursor updates the list, keeping the cursor on the same
+// item, or in the same place if it has gone
+func (m *model) updateListKeepingCursor() tea.Cmd {
+	index := m.list.Index()
+	id := 0
+	if item, ok := m.list.SelectedItem().(TUIItem); ok {
+		id = item.ID
+	}
+
+	cmd := m.UpdateList()
+	m.selectItem(id, index)
+
+	return cmd
+}
+
+// selectItem moves the list cursor to an item, or to index if it isn't shown
+func (m *model) selectItem(ID int, index int) {
+	items := m.list.VisibleItems()
+	for i, item := range items {
+		if item.(TUIItem).ID == ID {
+			m.list.Select(i)
+			return
+		}
+	}
+
+	m.list.Select(min(index, max(len(items)-1, 0)))
+}
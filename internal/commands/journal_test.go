@@ -0,0 +1,137 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestDiffStates(t *testing.T) {
+	readAt := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	before := map[int]itemState{
+		1: {},
+		2: {tags: []string{"go"}},
+		3: {readAt: readAt},
+		4: {},
+	}
+	after := map[int]itemState{
+		1: {readAt: readAt},
+		2: {tags: []string{"go", "later"}},
+		3: {readAt: readAt},
+		// fetched in between
+		5: {readAt: readAt},
+	}
+
+	ch := diffStates("read", before, after)
+	test.Equal(t, 2, len(ch.after), "only changed items")
+	test.Equal(t, true, ch.before[1].readAt.IsZero(), "unread before")
+	test.Equal(t, true, ch.after[1].readAt.Equal(readAt), "read at kept exactly")
+	test.Equal(t, 1, len(ch.before[2].tags), "tags before")
+	test.Equal(t, 2, len(ch.after[2].tags), "tags after")
+
+	ch = diffStates("favourite", before, before)
+	test.Equal(t, 0, len(ch.after), "no-op isn't journalled")
+}
+
+// autoReadModel has an article opened, and so read, with autoread on
+func autoReadModel(t *testing.T) (model, func() bool) {
+	const feedURL = "https://example.com/feed"
+
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+	id, _, err := s.UpsertItem(store.Item{FeedURL: feedURL, Title: "First", Content: "Hello"})
+	test.HandleError(t, err)
+
+	cfg := &config.Config{AutoRead: true, ShowRead: true, Feeds: []config.Feed{{URL: feedURL}}}
+	m := model{
+		cfg:      cfg,
+		commands: New(cfg, s),
+		list:     list.New(nil, itemDelegate{}, 80, 20),
+		journal:  &journal{},
+	}
+	m.selectedArticle = &id
+	_, err = m.openArticle(id)
+	test.HandleError(t, err)
+
+	read := func() bool {
+		item, err := s.GetItemByID(id)
+		test.HandleError(t, err)
+		return item.Read()
+	}
+	test.Equal(t, true, read(), "read on opening")
+
+	return m, read
+}
+
+func TestUndoReadWithAutoRead(t *testing.T) {
+	m, read := autoReadModel(t)
+
+	undo, _ := keyMsgFor(ViewportKeyMap.Undo)
+	next, _ := m.Update(undo)
+	m = next.(model)
+	test.Equal(t, "Undid read.", m.status, "undo status")
+	test.Equal(t, false, read(), "undone read stays unread in the article")
+
+	redo, _ := keyMsgFor(ViewportKeyMap.Redo)
+	next, _ = m.Update(redo)
+	m = next.(model)
+	test.Equal(t, "Redid read.", m.status, "redo status")
+	test.Equal(t, true, read(), "read again on redo")
+}
+
+func TestImagesFetchedKeepsUndoneRead(t *testing.T) {
+	m, read := autoReadModel(t)
+
+	undo, _ := keyMsgFor(ViewportKeyMap.Undo)
+	next, _ := m.Update(undo)
+	m = next.(model)
+
+	next, _ = m.Update(imagesFetched{id: *m.selectedArticle, drawn: 1})
+	m = next.(model)
+	test.Equal(t, false, read(), "drawing images doesn't mark it read again")
+
+	redo, _ := keyMsgFor(ViewportKeyMap.Redo)
+	next, _ = m.Update(redo)
+	test.Equal(t, "Redid read.", next.(model).status, "undo kept in the journal")
+	test.Equal(t, true, read(), "read again on redo")
+}
+
+func TestRecordClusterCopies(t *testing.T) {
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	var ids []int
+	for _, feed := range []string{"https://a.example/feed", "https://b.example/feed", "https://c.example/feed"} {
+		id, _, err := s.UpsertItem(store.Item{FeedURL: feed, Title: "Same story"})
+		test.HandleError(t, err)
+		ids = append(ids, id)
+	}
+	// the third is a different story
+	test.HandleError(t, s.SetClusters(map[int]int{ids[0]: ids[0], ids[1]: ids[0]}))
+
+	cfg := &config.Config{Dedupe: config.DedupeConfig{Enabled: true}}
+	c := New(cfg, s)
+	j := &journal{}
+
+	err = j.record(c, "read", []int{ids[0]}, func() error {
+		return c.toggleRead(ids[0])
+	})
+	test.HandleError(t, err)
+	test.Equal(t, 2, len(j.done[0].after), "copy in the other feed journalled")
+
+	_, err = j.undo(c)
+	test.HandleError(t, err)
+
+	states, err := c.itemStates(nil)
+	test.HandleError(t, err)
+	test.Equal(t, 3, len(states), "every item for nil IDs")
+	for _, st := range states {
+		test.Equal(t, true, st.readAt.IsZero(), "unread again after undo")
+	}
+}
@@ -23,8 +23,39 @@ type ListKeyMapT struct {
 	oCancelWhileFiltering key.Binding
 	oNextPage             key.Binding
 	oPrevPage             key.Binding
+	oCursorUp             key.Binding
+	oCursorDown           key.Binding
+	oFilter               key.Binding
+	oGoToStart            key.Binding
+	oGoToEnd              key.Binding
+	oShowFullHelp         key.Binding
+	oCloseFullHelp        key.Binding
 	EditConfig            key.Binding
 	Suspend               key.Binding
+	Tag                   key.Binding
+	ReadLater             key.Binding
+	ToggleReadLater       key.Binding
+	MoveUp                key.Binding
+	MoveDown              key.Binding
+	SwitchView            key.Binding
+	ToggleSidebar         key.Binding
+	FocusSidebar          key.Binding
+	TogglePreview         key.Binding
+	FocusPreview          key.Binding
+	GrowList              key.Binding
+	ShrinkList            key.Binding
+	Select                key.Binding
+	SelectRange           key.Binding
+	SelectAll             key.Binding
+	ClearSelection        key.Binding
+	Undo                  key.Binding
+	Redo                  key.Binding
+	MarkFeedRead          key.Binding
+	MarkAboveRead         key.Binding
+	MarkBelowRead         key.Binding
+	YankURL               key.Binding
+	YankTitle             key.Binding
+	YankMarkdown          key.Binding
 }
 
 // ViewportKeyMapT shows *all* keybinds, pulling from viewport.DefaultKeyMap()
@@ -41,6 +72,76 @@ type ViewportKeyMapT struct {
 	ShowFullHelp  key.Binding
 	CloseFullHelp key.Binding
 	Suspend       key.Binding
+	OpenEnclosure key.Binding
+	Download      key.Binding
+	Tag           key.Binding
+	ReadLater     key.Binding
+	Note          key.Binding
+	FullText      key.Binding
+	Up            key.Binding
+	Down          key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	HalfPageUp    key.Binding
+	HalfPageDown  key.Binding
+	Undo          key.Binding
+	Redo          key.Binding
+	Links         key.Binding
+	YankURL       key.Binding
+	YankTitle     key.Binding
+	YankMarkdown  key.Binding
+	Search        key.Binding
+	SearchBack    key.Binding
+	NextMatch     key.Binding
+	PrevMatch     key.Binding
+	ClearSearch   key.Binding
+}
+
+// TagPickerKeyMapT is used while the tag picker popup is open
+type TagPickerKeyMapT struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	Close  key.Binding
+}
+
+// LinkPickerKeyMapT is used while the link picker popup is open
+type LinkPickerKeyMapT struct {
+	Up    key.Binding
+	Down  key.Binding
+	Open  key.Binding
+	Copy  key.Binding
+	Close key.Binding
+}
+
+// SearchKeyMapT is used while a search is typed in the article view
+type SearchKeyMapT struct {
+	Confirm key.Binding
+	Cancel  key.Binding
+}
+
+// PreviewKeyMapT is used while the preview pane has focus, other keys scroll
+type PreviewKeyMapT struct {
+	Blur          key.Binding
+	Open          key.Binding
+	OpenInBrowser key.Binding
+	GotoStart     key.Binding
+	GotoEnd       key.Binding
+	Quit          key.Binding
+}
+
+// SidebarKeyMapT is used while the sidebar has focus
+type SidebarKeyMapT struct {
+	Up       key.Binding
+	Down     key.Binding
+	Select   key.Binding
+	Toggle   key.Binding
+	MarkRead key.Binding
+	Expand   key.Binding
+	Collapse key.Binding
+	Focus    key.Binding
+	Close    key.Binding
+	Quit     key.Binding
 }
 
 // ListKeyMap shows either (o)verrides or new keybinds
@@ -67,7 +168,31 @@ var ListKeyMap = ListKeyMapT{
 	),
 	MarkAllRead: key.NewBinding(
 		key.WithKeys("alt+m"),
-		key.WithHelp("alt+m", "mark all read"),
+		key.WithHelp("alt+m", "mark all shown read"),
+	),
+	MarkFeedRead: key.NewBinding(
+		key.WithKeys("alt+f"),
+		key.WithHelp("alt+f", "mark feed read"),
+	),
+	MarkAboveRead: key.NewBinding(
+		key.WithKeys("alt+k"),
+		key.WithHelp("alt+k", "mark above read"),
+	),
+	MarkBelowRead: key.NewBinding(
+		key.WithKeys("alt+j"),
+		key.WithHelp("alt+j", "mark below read"),
+	),
+	YankURL: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy url"),
+	),
+	YankTitle: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy title and url"),
+	),
+	YankMarkdown: key.NewBinding(
+		key.WithKeys("alt+y"),
+		key.WithHelp("alt+y", "copy markdown link"),
 	),
 	Refresh: key.NewBinding(
 		key.WithKeys("r"),
@@ -85,6 +210,78 @@ var ListKeyMap = ListKeyMapT{
 		key.WithKeys("E"),
 		key.WithHelp("E", "edit config in $EDITOR"),
 	),
+	Tag: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tag"),
+	),
+	ReadLater: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "read later"),
+	),
+	ToggleReadLater: key.NewBinding(
+		key.WithKeys("B"),
+		key.WithHelp("B", "toggle show read later"),
+	),
+	MoveUp: key.NewBinding(
+		key.WithKeys("K"),
+		key.WithHelp("K", "move up in read later"),
+	),
+	MoveDown: key.NewBinding(
+		key.WithKeys("J"),
+		key.WithHelp("J", "move down in read later"),
+	),
+	SwitchView: key.NewBinding(
+		key.WithKeys("1", "2", "3", "4", "5", "6", "7", "8", "9", "0"),
+		key.WithHelp("1-9/0", "switch view/all"),
+	),
+	ToggleSidebar: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "toggle sidebar"),
+	),
+	FocusSidebar: key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "focus sidebar"),
+	),
+	TogglePreview: key.NewBinding(
+		key.WithKeys("P"),
+		key.WithHelp("P", "toggle preview"),
+	),
+	FocusPreview: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "focus preview"),
+	),
+	GrowList: key.NewBinding(
+		key.WithKeys(">"),
+		key.WithHelp(">", "grow list"),
+	),
+	ShrinkList: key.NewBinding(
+		key.WithKeys("<"),
+		key.WithHelp("<", "shrink list"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "select"),
+	),
+	SelectRange: key.NewBinding(
+		key.WithKeys("V"),
+		key.WithHelp("V", "select range"),
+	),
+	SelectAll: key.NewBinding(
+		key.WithKeys("ctrl+a"),
+		key.WithHelp("ctrl+a", "select all shown"),
+	),
+	ClearSelection: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear selection"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
 	Suspend: key.NewBinding(
 		key.WithKeys("ctrl+z"),
 		key.WithHelp("ctrl+z", "suspend"),
@@ -114,6 +311,13 @@ var ListKeyMap = ListKeyMapT{
 		key.WithKeys("right", "l", "pgdown"),
 		key.WithHelp("→/l/pgdn", "next page"),
 	),
+	oCursorUp:      list.DefaultKeyMap().CursorUp,
+	oCursorDown:    list.DefaultKeyMap().CursorDown,
+	oFilter:        list.DefaultKeyMap().Filter,
+	oGoToStart:     list.DefaultKeyMap().GoToStart,
+	oGoToEnd:       list.DefaultKeyMap().GoToEnd,
+	oShowFullHelp:  list.DefaultKeyMap().ShowFullHelp,
+	oCloseFullHelp: list.DefaultKeyMap().CloseFullHelp,
 }
 
 // ViewportKeyMapT shows *all* keybinds, pulling from viewport.DefaultKeyMap()
@@ -150,6 +354,74 @@ var ViewportKeyMap = ViewportKeyMapT{
 		key.WithKeys("m"),
 		key.WithHelp("m", "mark read"),
 	),
+	Tag: key.NewBinding(
+		key.WithKeys("t"),
+		key.WithHelp("t", "tag"),
+	),
+	ReadLater: key.NewBinding(
+		key.WithKeys("b"),
+		key.WithHelp("b", "read later"),
+	),
+	Note: key.NewBinding(
+		key.WithKeys("e"),
+		key.WithHelp("e", "edit note"),
+	),
+	Search: key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	),
+	SearchBack: key.NewBinding(
+		key.WithKeys("?"),
+		key.WithHelp("?", "search back"),
+	),
+	NextMatch: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "next match"),
+	),
+	PrevMatch: key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "prev match"),
+	),
+	ClearSearch: key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "clear search"),
+	),
+	Links: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L/1-9", "links"),
+	),
+	YankURL: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "copy url"),
+	),
+	YankTitle: key.NewBinding(
+		key.WithKeys("Y"),
+		key.WithHelp("Y", "copy title and url"),
+	),
+	YankMarkdown: key.NewBinding(
+		key.WithKeys("alt+y"),
+		key.WithHelp("alt+y", "copy markdown link"),
+	),
+	FullText: key.NewBinding(
+		key.WithKeys("x"),
+		key.WithHelp("x", "fetch full text"),
+	),
+	Undo: key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "undo"),
+	),
+	Redo: key.NewBinding(
+		key.WithKeys("ctrl+r"),
+		key.WithHelp("ctrl+r", "redo"),
+	),
+	OpenEnclosure: key.NewBinding(
+		key.WithKeys("p"),
+		key.WithHelp("p", "play enclosure"),
+	),
+	Download: key.NewBinding(
+		key.WithKeys("D"),
+		key.WithHelp("D", "download enclosure"),
+	),
 	GotoStart: key.NewBinding(
 		key.WithKeys("g", "home"),
 		key.WithHelp("g", "top"),
@@ -159,31 +431,180 @@ var ViewportKeyMap = ViewportKeyMapT{
 		key.WithHelp("G", "bottom"),
 	),
 	ShowFullHelp: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "more"),
+		key.WithKeys("H"),
+		key.WithHelp("H", "more"),
 	),
 	CloseFullHelp: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "close help"),
+		key.WithKeys("H"),
+		key.WithHelp("H", "close help"),
+	),
+	// the viewport's own keys, less f, b and u which are used above
+	Up:           viewport.DefaultKeyMap().Up,
+	Down:         viewport.DefaultKeyMap().Down,
+	HalfPageDown: viewport.DefaultKeyMap().HalfPageDown,
+	HalfPageUp: key.NewBinding(
+		key.WithKeys("ctrl+u"),
+		key.WithHelp("ctrl+u", "½ page up"),
+	),
+	PageUp: key.NewBinding(
+		key.WithKeys("pgup"),
+		key.WithHelp("pgup", "page up"),
+	),
+	PageDown: key.NewBinding(
+		key.WithKeys("pgdown", " "),
+		key.WithHelp("pgdn/space", "page down"),
+	),
+}
+
+var TagPickerKeyMap = TagPickerKeyMapT{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+p", "ctrl+k"),
+		key.WithHelp("↑", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+n", "ctrl+j"),
+		key.WithHelp("↓", "down"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "toggle tag"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "close"),
+	),
+}
+
+var LinkPickerKeyMap = LinkPickerKeyMapT{
+	Up: key.NewBinding(
+		key.WithKeys("up", "ctrl+p", "ctrl+k"),
+		key.WithHelp("↑", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("down", "ctrl+n", "ctrl+j"),
+		key.WithHelp("↓", "down"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open"),
+	),
+	Copy: key.NewBinding(
+		key.WithKeys("ctrl+y"),
+		key.WithHelp("ctrl+y", "copy"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "close"),
+	),
+}
+
+var SearchKeyMap = SearchKeyMapT{
+	Confirm: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "search"),
+	),
+	Cancel: key.NewBinding(
+		key.WithKeys("esc", "ctrl+c"),
+		key.WithHelp("esc", "cancel"),
+	),
+}
+
+var PreviewKeyMap = PreviewKeyMapT{
+	Blur: key.NewBinding(
+		key.WithKeys("p", "esc", "q"),
+		key.WithHelp("p/esc", "focus list"),
+	),
+	Open: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "open"),
+	),
+	OpenInBrowser: key.NewBinding(
+		key.WithKeys("o"),
+		key.WithHelp("o", "open in browser"),
+	),
+	GotoStart: key.NewBinding(
+		key.WithKeys("g", "home"),
+		key.WithHelp("g", "top"),
+	),
+	GotoEnd: key.NewBinding(
+		key.WithKeys("G", "end"),
+		key.WithHelp("G", "bottom"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("ctrl+c"),
+		key.WithHelp("ctrl+c", "quit"),
+	),
+}
+
+var SidebarKeyMap = SidebarKeyMapT{
+	Up: key.NewBinding(
+		key.WithKeys("k", "up"),
+		key.WithHelp("↑/k", "up"),
+	),
+	Down: key.NewBinding(
+		key.WithKeys("j", "down"),
+		key.WithHelp("↓/j", "down"),
+	),
+	Select: key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "show items"),
+	),
+	Toggle: key.NewBinding(
+		key.WithKeys(" "),
+		key.WithHelp("space", "fold group"),
+	),
+	MarkRead: key.NewBinding(
+		key.WithKeys("m"),
+		key.WithHelp("m", "mark read"),
+	),
+	Expand: key.NewBinding(
+		key.WithKeys("l", "right"),
+		key.WithHelp("l/→", "expand"),
+	),
+	Collapse: key.NewBinding(
+		key.WithKeys("h", "left"),
+		key.WithHelp("h/←", "collapse"),
+	),
+	Focus: key.NewBinding(
+		key.WithKeys("tab", "esc"),
+		key.WithHelp("tab/esc", "focus list"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("S"),
+		key.WithHelp("S", "close sidebar"),
+	),
+	Quit: key.NewBinding(
+		key.WithKeys("q", "ctrl+c"),
+		key.WithHelp("q", "quit"),
 	),
 }
 
 // This show *all* keybinds, as bubbles/viewport doesn't provide a help function
 func (k ViewportKeyMapT) FullHelp() [][]key.Binding {
-	v := viewport.DefaultKeyMap()
 	return [][]key.Binding{
-		{v.Up, v.Down, v.HalfPageUp, v.HalfPageDown},
-		{k.GotoStart, k.GotoEnd, v.PageUp, v.PageDown},
-		{k.Next, k.Prev, k.OpenInBrowser, k.Favourite, k.Read},
-		{k.Escape, k.Quit, k.CloseFullHelp},
+		{k.Up, k.Down, k.HalfPageUp, k.HalfPageDown, k.GotoStart, k.GotoEnd, k.PageUp, k.PageDown},
+		{k.Next, k.Prev, k.OpenInBrowser, k.Links, k.Favourite, k.Read, k.Tag, k.ReadLater, k.Note},
+		{k.OpenEnclosure, k.Download, k.FullText, k.Undo, k.Redo, k.Escape, k.Quit, k.CloseFullHelp},
+		{k.YankURL, k.YankTitle, k.YankMarkdown, k.Search, k.SearchBack, k.NextMatch, k.PrevMatch, k.ClearSearch},
 	}
 }
 
 // This show *all* keybinds, as bubbles/viewport doesn't provide a help function
 func (k ViewportKeyMapT) ShortHelp() []key.Binding {
-	v := viewport.DefaultKeyMap()
 	return []key.Binding{
-		k.Next, k.Prev, v.Down, v.Up, k.Escape, k.ShowFullHelp,
+		k.Next, k.Prev, k.Down, k.Up, k.Escape, k.ShowFullHelp,
+	}
+}
+
+// ViewportKeys are the scrolling keys for viewports showing articles
+func (k ViewportKeyMapT) ViewportKeys() viewport.KeyMap {
+	return viewport.KeyMap{
+		Up:           k.Up,
+		Down:         k.Down,
+		PageUp:       k.PageUp,
+		PageDown:     k.PageDown,
+		HalfPageUp:   k.HalfPageUp,
+		HalfPageDown: k.HalfPageDown,
 	}
 }
 
@@ -192,7 +613,13 @@ func (k ListKeyMapT) FullHelp() []key.Binding {
 	return []key.Binding{
 		k.Open, k.Read, k.Favourite, k.Refresh,
 		k.OpenInBrowser, k.Sort, k.ToggleFavourites, k.ToggleReads,
-		k.MarkAllRead, k.EditConfig,
+		k.MarkAllRead, k.MarkFeedRead, k.MarkAboveRead, k.MarkBelowRead,
+		k.Tag, k.ReadLater, k.ToggleReadLater,
+		k.MoveUp, k.MoveDown, k.SwitchView, k.ToggleSidebar,
+		k.FocusSidebar, k.TogglePreview, k.FocusPreview, k.GrowList,
+		k.ShrinkList, k.Select, k.SelectRange, k.SelectAll,
+		k.ClearSelection, k.Undo, k.Redo, k.YankURL,
+		k.YankTitle, k.YankMarkdown, k.EditConfig,
 	}
 }
 
@@ -216,4 +643,11 @@ func (k ListKeyMapT) SetOverrides(l *list.Model) {
 	l.KeyMap.NextPage.SetHelp(k.oNextPage.Help().Key, k.oNextPage.Help().Desc)
 	l.KeyMap.PrevPage.SetKeys(k.oPrevPage.Keys()...)
 	l.KeyMap.PrevPage.SetHelp(k.oPrevPage.Help().Key, k.oPrevPage.Help().Desc)
+	l.KeyMap.CursorUp = k.oCursorUp
+	l.KeyMap.CursorDown = k.oCursorDown
+	l.KeyMap.Filter = k.oFilter
+	l.KeyMap.GoToStart = k.oGoToStart
+	l.KeyMap.GoToEnd = k.oGoToEnd
+	l.KeyMap.ShowFullHelp = k.oShowFullHelp
+	l.KeyMap.CloseFullHelp = k.oCloseFullHelp
 }
@@ -0,0 +1,229 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+// keyAction is a binding that can be remapped in the keys config
+type keyAction struct {
+	name    string
+	binding *key.Binding
+	// actions in the same group are used at different times, such as quit
+	// and clearing a filter, so can share keys
+	group string
+}
+
+func (k *ListKeyMapT) actions() []keyAction {
+	return []keyAction{
+		{name: "up", binding: &k.oCursorUp},
+		{name: "down", binding: &k.oCursorDown},
+		{name: "nextpage", binding: &k.oNextPage},
+		{name: "prevpage", binding: &k.oPrevPage},
+		{name: "gotostart", binding: &k.oGoToStart},
+		{name: "gotoend", binding: &k.oGoToEnd},
+		{name: "filter", binding: &k.oFilter},
+		{name: "open", binding: &k.Open},
+		{name: "read", binding: &k.Read},
+		{name: "favourite", binding: &k.Favourite},
+		{name: "togglereads", binding: &k.ToggleReads},
+		{name: "markallread", binding: &k.MarkAllRead},
+		{name: "markfeedread", binding: &k.MarkFeedRead},
+		{name: "markaboveread", binding: &k.MarkAboveRead},
+		{name: "markbelowread", binding: &k.MarkBelowRead},
+		{name: "yankurl", binding: &k.YankURL},
+		{name: "yanktitle", binding: &k.YankTitle},
+		{name: "yankmarkdown", binding: &k.YankMarkdown},
+		{name: "togglefavourites", binding: &k.ToggleFavourites},
+		{name: "refresh", binding: &k.Refresh},
+		{name: "openinbrowser", binding: &k.OpenInBrowser},
+		{name: "sort", binding: &k.Sort},
+		{name: "tag", binding: &k.Tag},
+		{name: "readlater", binding: &k.ReadLater},
+		{name: "togglereadlater", binding: &k.ToggleReadLater},
+		{name: "moveup", binding: &k.MoveUp},
+		{name: "movedown", binding: &k.MoveDown},
+		{name: "switchview", binding: &k.SwitchView},
+		{name: "togglesidebar", binding: &k.ToggleSidebar},
+		{name: "focussidebar", binding: &k.FocusSidebar},
+		{name: "togglepreview", binding: &k.TogglePreview},
+		{name: "focuspreview", binding: &k.FocusPreview},
+		{name: "growlist", binding: &k.GrowList},
+		{name: "shrinklist", binding: &k.ShrinkList},
+		{name: "select", binding: &k.Select},
+		{name: "selectrange", binding: &k.SelectRange},
+		{name: "selectall", binding: &k.SelectAll},
+		{name: "clearselection", binding: &k.ClearSelection, group: "quit"},
+		{name: "undo", binding: &k.Undo},
+		{name: "redo", binding: &k.Redo},
+		{name: "editconfig", binding: &k.EditConfig},
+		{name: "suspend", binding: &k.Suspend},
+		{name: "showfullhelp", binding: &k.oShowFullHelp, group: "help"},
+		{name: "closefullhelp", binding: &k.oCloseFullHelp, group: "help"},
+		{name: "quit", binding: &k.oQuit, group: "quit"},
+		{name: "forcequit", binding: &k.oForceQuit},
+		{name: "clearfilter", binding: &k.oClearFilter, group: "quit"},
+		{name: "cancelwhilefiltering", binding: &k.oCancelWhileFiltering, group: "quit"},
+	}
+}
+
+func (k *ViewportKeyMapT) actions() []keyAction {
+	return []keyAction{
+		{name: "up", binding: &k.Up},
+		{name: "down", binding: &k.Down},
+		{name: "pageup", binding: &k.PageUp},
+		{name: "pagedown", binding: &k.PageDown},
+		{name: "halfpageup", binding: &k.HalfPageUp},
+		{name: "halfpagedown", binding: &k.HalfPageDown},
+		{name: "gotostart", binding: &k.GotoStart},
+		{name: "gotoend", binding: &k.GotoEnd},
+		{name: "next", binding: &k.Next},
+		{name: "prev", binding: &k.Prev},
+		{name: "openinbrowser", binding: &k.OpenInBrowser},
+		{name: "links", binding: &k.Links},
+		{name: "yankurl", binding: &k.YankURL},
+		{name: "yanktitle", binding: &k.YankTitle},
+		{name: "yankmarkdown", binding: &k.YankMarkdown},
+		{name: "favourite", binding: &k.Favourite},
+		{name: "read", binding: &k.Read},
+		{name: "tag", binding: &k.Tag},
+		{name: "readlater", binding: &k.ReadLater},
+		{name: "note", binding: &k.Note},
+		{name: "fulltext", binding: &k.FullText},
+		{name: "openenclosure", binding: &k.OpenEnclosure},
+		{name: "download", binding: &k.Download},
+		{name: "search", binding: &k.Search},
+		{name: "searchback", binding: &k.SearchBack},
+		{name: "nextmatch", binding: &k.NextMatch},
+		{name: "prevmatch", binding: &k.PrevMatch},
+		{name: "clearsearch", binding: &k.ClearSearch, group: "quit"},
+		{name: "undo", binding: &k.Undo},
+		{name: "redo", binding: &k.Redo},
+		{name: "suspend", binding: &k.Suspend},
+		{name: "showfullhelp", binding: &k.ShowFullHelp, group: "help"},
+		{name: "closefullhelp", binding: &k.CloseFullHelp, group: "help"},
+		{name: "escape", binding: &k.Escape, group: "quit"},
+		{name: "quit", binding: &k.Quit},
+	}
+}
+
+// ApplyKeys remaps the list and article view keys from the config, failing
+// if an action is unknown or a key ends up bound to two actions
+func ApplyKeys(cfg config.KeyConfig) error {
+	err := remapKeys("list", ListKeyMap.actions(), cfg.List)
+	if err != nil {
+		return fmt.Errorf("ApplyKeys: %w", err)
+	}
+
+	err = remapKeys("viewport", ViewportKeyMap.actions(), cfg.Viewport)
+	if err != nil {
+		return fmt.Errorf("ApplyKeys: %w", err)
+	}
+
+	return nil
+}
+
+func remapKeys(view string, actions []keyAction, keys map[string]config.Keys) error {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	// report the same error every time
+	slices.Sort(names)
+
+	for _, name := range names {
+		i := slices.IndexFunc(actions, func(a keyAction) bool {
+			return a.name == strings.ToLower(name)
+		})
+		if i < 0 {
+			return fmt.Errorf("keys %s: unknown action %q", view, name)
+		}
+
+		ks := make([]string, 0, len(keys[name]))
+		for _, k := range keys[name] {
+			// bubbletea calls the space bar " "
+			if k == "space" {
+				k = " "
+			}
+			ks = append(ks, k)
+		}
+
+		b := actions[i].binding
+		b.SetKeys(ks...)
+		b.SetHelp(keysHelp(ks), b.Help().Desc)
+	}
+
+	bound := make(map[string]keyAction)
+	for _, a := range actions {
+		for _, k := range a.binding.Keys() {
+			if b, ok := bound[k]; ok && (a.group == "" || a.group != b.group) {
+				return fmt.Errorf("keys %s: %s is bound to both %s and %s", view, keyName(k), b.name, a.name)
+			}
+			bound[k] = a
+		}
+	}
+
+	return nil
+}
+
+var keySymbols = map[string]string{
+	" ":      "space",
+	"up":     "↑",
+	"down":   "↓",
+	"left":   "←",
+	"right":  "→",
+	"pgup":   "pgup",
+	"pgdown": "pgdn",
+}
+
+func keyName(k string) string {
+	if s, ok := keySymbols[k]; ok {
+		return s
+	}
+
+	return k
+}
+
+// keysHelp is the short help for remapped keys, in the style of the defaults
+func keysHelp(keys []string) string {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, keyName(k))
+	}
+
+	return strings.Join(names, "/")
+}
+
+// ShowKeys prints the actions of each view with their keys, after remapping
+func (c Commands) ShowKeys() error {
+	views := []struct {
+		name    string
+		actions []keyAction
+	}{
+		{"list", ListKeyMap.actions()},
+		{"viewport", ViewportKeyMap.actions()},
+	}
+
+	for i, v := range views {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s:\n", v.name)
+
+		for _, a := range v.actions {
+			keys := make([]string, 0, len(a.binding.Keys()))
+			for _, k := range a.binding.Keys() {
+				keys = append(keys, keyName(k))
+			}
+
+			fmt.Printf("  %-22s %-20s %s\n", a.name, strings.Join(keys, " "), a.binding.Help().Desc)
+		}
+	}
+
+	return nil
+}
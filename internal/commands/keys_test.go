@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestRemapKeys(t *testing.T) {
+	// remap a copy so other tests keep the defaults
+	km := ListKeyMap
+	err := remapKeys("list", km.actions(), map[string]config.Keys{
+		"Read":    {"x", "space"},
+		"refresh": {"R"},
+		"select":  {"v"},
+	})
+	test.HandleError(t, err)
+
+	test.Equal(t, "x/space", km.Read.Help().Key, "help from the new keys")
+	test.Equal(t, "mark read", km.Read.Help().Desc, "help keeps its description")
+	test.Equal(t, " ", km.Read.Keys()[1], "space is the space bar")
+	test.Equal(t, "R", km.Refresh.Keys()[0], "remapped refresh")
+	test.Equal(t, "m", ListKeyMap.Read.Keys()[0], "defaults left alone")
+}
+
+func TestRemapKeysErrors(t *testing.T) {
+	km := ListKeyMap
+	err := remapKeys("list", km.actions(), map[string]config.Keys{"nope": {"x"}})
+	if err == nil || !strings.Contains(err.Error(), `unknown action "nope"`) {
+		t.Fatalf("expected an unknown action error, got %v", err)
+	}
+
+	km = ListKeyMap
+	err = remapKeys("list", km.actions(), map[string]config.Keys{"read": {"f"}})
+	if err == nil || !strings.Contains(err.Error(), "f is bound to both") {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+
+	// quit and clearing a filter share keys by default
+	vm := ViewportKeyMap
+	test.HandleError(t, remapKeys("viewport", vm.actions(), nil))
+	km = ListKeyMap
+	test.HandleError(t, remapKeys("list", km.actions(), map[string]config.Keys{"quit": {"x"}, "clearfilter": {"x"}}))
+}
@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sahilm/fuzzy"
+)
+
+// maximum number of links shown in the picker at once
+const linkPickerHeight = 12
+
+// linkPicker is a popup listing the links and images of the open article.
+// Typing a number picks that link, anything else narrows them fuzzily.
+type linkPicker struct {
+	links  []articleLink
+	input  textinput.Model
+	cursor int
+}
+
+func (m model) openLinkPicker(value string) (model, tea.Cmd) {
+	links, err := m.commands.articleLinks(*m.selectedArticle)
+	if err != nil {
+		m.status = err.Error()
+		return m, nil
+	}
+	if len(links) == 0 {
+		m.status = "No links."
+		return m, nil
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "number or search"
+	ti.Prompt = "> "
+	ti.SetValue(value)
+	ti.Focus()
+
+	m.linkPicker = &linkPicker{links: links, input: ti}
+
+	return m, textinput.Blink
+}
+
+// options returns the links matching the input. Numbers match links whose
+// number starts with them, so the exact one comes first.
+func (p *linkPicker) options() []articleLink {
+	term := strings.TrimSpace(p.input.Value())
+	if term == "" {
+		return p.links
+	}
+
+	if _, err := strconv.Atoi(term); err == nil {
+		var opts []articleLink
+		for _, l := range p.links {
+			if strings.HasPrefix(strconv.Itoa(l.n), term) {
+				opts = append(opts, l)
+			}
+		}
+		return opts
+	}
+
+	targets := make([]string, len(p.links))
+	for i, l := range p.links {
+		targets[i] = l.text + " " + l.url
+	}
+
+	var opts []articleLink
+	for _, match := range fuzzy.Find(term, targets) {
+		opts = append(opts, p.links[match.Index])
+	}
+
+	return opts
+}
+
+func (p *linkPicker) current() (articleLink, bool) {
+	opts := p.options()
+	if len(opts) == 0 {
+		return articleLink{}, false
+	}
+
+	return opts[min(p.cursor, len(opts)-1)], true
+}
+
+func updateLinkPicker(keyMsg tea.KeyMsg, m model) (tea.Model, tea.Cmd) {
+	p := m.linkPicker
+
+	switch {
+	case key.Matches(keyMsg, LinkPickerKeyMap.Close):
+		m.linkPicker = nil
+		return m, nil
+
+	case key.Matches(keyMsg, LinkPickerKeyMap.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+
+	case key.Matches(keyMsg, LinkPickerKeyMap.Down):
+		if p.cursor < len(p.options())-1 {
+			p.cursor++
+		}
+		return m, nil
+
+	case key.Matches(keyMsg, LinkPickerKeyMap.Open):
+		l, ok := p.current()
+		if !ok {
+			return m, nil
+		}
+
+		m.linkPicker = nil
+		m.status = "Opening..."
+		return m, m.OpenLink(l.url)
+
+	case key.Matches(keyMsg, LinkPickerKeyMap.Copy):
+		l, ok := p.current()
+		if !ok {
+			return m, nil
+		}
+
+		m.linkPicker = nil
+		return m, m.commands.copyToClipboard(l.url, "Copied "+l.url)
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(keyMsg)
+	p.cursor = 0
+
+	return m, cmd
+}
+
+// start is the first option shown, keeping the cursor in view
+func (p *linkPicker) start() int {
+	return max(0, p.cursor-linkPickerHeight+1)
+}
+
+// linkPickerBox is the picker before it is placed in the middle of the screen
+func linkPickerBox(m model) string {
+	p := m.linkPicker
+	width := min(max(m.width-4, 40), 100)
+
+	var b strings.Builder
+	b.WriteString(pickerTitleStyle.Render("Links"))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+
+	opts := p.options()
+	if len(opts) == 0 {
+		b.WriteString(pickerHelpStyle.Render("no matching links"))
+		b.WriteString("\n")
+	}
+
+	start := p.start()
+	for i := start; i < len(opts) && i < start+linkPickerHeight; i++ {
+		l := opts[i]
+		text := l.text
+		if l.image {
+			text = strings.TrimSpace("image: " + text)
+		}
+
+		cursor := "  "
+		if i == p.cursor {
+			cursor = "> "
+		}
+
+		// the url follows the text, dimmed, in what room is left
+		line := truncate(fmt.Sprintf("%s%d %s", cursor, l.n, text), width-4)
+		if text == "" {
+			line = truncate(fmt.Sprintf("%s%d %s", cursor, l.n, l.url), width-4)
+		} else if room := width - 6 - lipgloss.Width(line); room > 10 {
+			line += "  " + pickerHelpStyle.Render(truncate(l.url, room))
+		}
+		if i == p.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.cfg.Theme.SelectedItemColor)).
+				Render(line)
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(pickerHelpStyle.Render(fmt.Sprintf("%s open • %s copy • %s close",
+		LinkPickerKeyMap.Open.Help().Key, LinkPickerKeyMap.Copy.Help().Key, LinkPickerKeyMap.Close.Help().Key)))
+
+	return pickerStyle.Width(width).Render(b.String())
+}
+
+func linkPickerView(m model) string {
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, linkPickerBox(m))
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/guyfedwards/nom/v2/internal/rss"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+var (
+	// link text can hold bracketed text, such as an image already numbered
+	mdImageRe  = regexp.MustCompile(`!\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(<?([^)\s>]+)>?(?:\s+"[^"]*")?\)`)
+	mdLinkRe   = regexp.MustCompile(`\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(<?([^)\s>]+)>?(?:\s+"[^"]*")?\)`)
+	mdEscapeRe = regexp.MustCompile(`\\(.)`)
+)
+
+// articleLink is a link or image in an article, numbered in the order it
+// first appears
+type articleLink struct {
+	n     int
+	text  string
+	url   string
+	image bool
+}
+
+// articleMarkdown is the body of an item as markdown, before its links are
+// numbered
+func articleMarkdown(item store.Item) string {
+	switch {
+	case item.FullText != "":
+		return htmlToMd(item.FullText)
+	case item.Gemtext != "":
+		return rss.GemtextToMarkdown(item.Gemtext, item.Link)
+	default:
+		return htmlToMd(item.Content)
+	}
+}
+
+// footnoteLinks swaps the links and images in mdown for numbered
+// references, like w3m, resolving them against base. A link used twice keeps
+// its first number. Code blocks are left alone. Without a definition the
+// numbers in brackets are left as they are by markdown.
+func footnoteLinks(mdown string, base string) (string, []articleLink) {
+	baseURL, _ := url.Parse(base)
+
+	var links []articleLink
+	numbers := make(map[string]int)
+	number := func(text, link string, image bool) int {
+		if ref, err := url.Parse(link); err == nil && baseURL != nil {
+			link = baseURL.ResolveReference(ref).String()
+		}
+		if n, ok := numbers[link]; ok {
+			return n
+		}
+
+		n := len(links) + 1
+		numbers[link] = n
+		links = append(links, articleLink{n: n, text: unescapeMd(text), url: link, image: image})
+		return n
+	}
+
+	lines := strings.Split(mdown, "\n")
+	code := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			code = !code
+			continue
+		}
+		if code {
+			continue
+		}
+
+		// images first, as they can sit inside links
+		line = mdImageRe.ReplaceAllStringFunc(line, func(s string) string {
+			m := mdImageRe.FindStringSubmatch(s)
+			n := number(m[1], m[2], true)
+			if m[1] == "" {
+				return fmt.Sprintf("[image] [%d]", n)
+			}
+			return fmt.Sprintf("[image: %s] [%d]", m[1], n)
+		})
+		line = mdLinkRe.ReplaceAllStringFunc(line, func(s string) string {
+			m := mdLinkRe.FindStringSubmatch(s)
+			n := number(m[1], m[2], false)
+			if m[1] == "" {
+				return fmt.Sprintf("[%d]", n)
+			}
+			return fmt.Sprintf("%s [%d]", m[1], n)
+		})
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n"), links
+}
+
+// formatLinks lists the numbered links under the article
+func formatLinks(links []articleLink) string {
+	var b strings.Builder
+	b.WriteString("## Links\n\n")
+	for _, l := range links {
+		fmt.Fprintf(&b, "- [%d] %s\n", l.n, l.url)
+	}
+
+	return b.String()
+}
+
+func unescapeMd(s string) string {
+	return mdEscapeRe.ReplaceAllString(s, "$1")
+}
+
+// articleLinks are the numbered links and images of an item, as shown in the
+// article view
+func (c Commands) articleLinks(ID int) ([]articleLink, error) {
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return nil, fmt.Errorf("articleLinks: %w", err)
+	}
+
+	_, links := footnoteLinks(articleMarkdown(item), item.Link)
+
+	return links, nil
+}
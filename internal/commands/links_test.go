@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFootnoteLinks(t *testing.T) {
+	mdown := "See [the docs](/docs \"Docs\") and [Go](https://go.dev).\n\n" +
+		"[![a gopher](gopher.png)](https://go.dev)\n\n" +
+		"```\n[not](a-link)\n```\n\n" +
+		"Back to [the docs](https://example.com/docs)."
+
+	out, links := footnoteLinks(mdown, "https://example.com/blog/post")
+
+	want := "See the docs [1] and Go [2].\n\n" +
+		"[image: a gopher] [3] [2]\n\n" +
+		"```\n[not](a-link)\n```\n\n" +
+		"Back to the docs [1]."
+	test.Equal(t, want, out, "links should be numbered")
+
+	test.Equal(t, 3, len(links), "repeated links share a number")
+	test.Equal(t, articleLink{n: 1, text: "the docs", url: "https://example.com/docs"}, links[0], "relative links are resolved")
+	test.Equal(t, articleLink{n: 3, text: "a gopher", url: "https://example.com/blog/gopher.png", image: true}, links[2], "images are numbered")
+}
+
+func TestArticleMarkdownGemini(t *testing.T) {
+	item := store.Item{FeedURL: "gemini://example.org/atom.xml", Link: "gemini://example.org/post.gmi", Content: "<p>Hello <em>there</em></p>"}
+	test.Equal(t, "Hello _there_", articleMarkdown(item), "atom content over gemini is html")
+
+	item.Gemtext = "# Hello\n=> /about.gmi About"
+	test.Equal(t, "# Hello\n\n→ [About](gemini://example.org/about.gmi)\n\n", articleMarkdown(item), "fetched gemtext")
+}
+
+func TestLinkPickerOptions(t *testing.T) {
+	p := &linkPicker{}
+	for i := 1; i <= 12; i++ {
+		p.links = append(p.links, articleLink{n: i, url: "https://example.com"})
+	}
+	p.links[1].text = "golang release notes"
+
+	p.input.SetValue("1")
+	test.Equal(t, 4, len(p.options()), "numbers match by prefix")
+	test.Equal(t, 1, p.options()[0].n, "the exact number first")
+
+	p.input.SetValue("relnotes")
+	test.Equal(t, 2, p.options()[0].n, "text matches fuzzily")
+}
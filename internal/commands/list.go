@@ -5,6 +5,7 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/guyfedwards/nom/v2/internal/config"
 	"github.com/guyfedwards/nom/v2/internal/constants"
+	"github.com/guyfedwards/nom/v2/internal/query"
 	"github.com/guyfedwards/nom/v2/internal/store"
 )
 
@@ -24,6 +26,7 @@ var (
 	selectedReadStyle      = lipgloss.NewStyle().PaddingLeft(2)
 	favouriteStyle         = itemStyle.PaddingLeft(2).Bold(true)
 	selectedFavouriteStyle = selectedItemStyle.Bold(true)
+	filterErrorStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
 	helpStyle              = list.DefaultStyles().
 				HelpStyle.
 				PaddingLeft(4).
@@ -32,7 +35,8 @@ var (
 )
 
 type itemDelegate struct {
-	theme config.Theme
+	theme    config.Theme
+	selected map[int]bool
 }
 
 func (d itemDelegate) Height() int                               { return 1 }
@@ -51,6 +55,24 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 		str = fmt.Sprintf("%3d. %s: %s", index+1, i.FeedName, i.Title)
 	}
 
+	if i.Score != 0 {
+		str += fmt.Sprintf(" [%+d]", i.Score)
+	}
+
+	if len(i.AlsoIn) > 0 {
+		str += " (also in: " + strings.Join(i.AlsoIn, ", ") + ")"
+	}
+
+	// keep to one line, as panes may sit beside the list. Every style
+	// takes up 4 cells before the text.
+	width := m.Width() - 4
+	if d.selected[i.ID] {
+		str = "+ " + str
+	}
+	if width > 1 {
+		str = truncate(str, width)
+	}
+
 	fn := itemStyle.Render
 
 	if i.Read {
@@ -87,17 +109,30 @@ func (m *model) UpdateList() tea.Cmd {
 		return tea.Quit
 	}
 
-	cmd := m.list.SetItems(convertItems(fs))
+	return m.setItems(convertItems(fs))
+}
 
-	return cmd
+// setItems replaces the list items, keeping the filter's view of them in sync
+func (m *model) setItems(items []list.Item) tea.Cmd {
+	m.refreshSidebar()
+	m.pruneSelected(items)
+	// the shown item may have been read, tagged or removed
+	if m.preview != nil {
+		m.preview.id = 0
+	}
+	m.list.Filter = CustomFilter(m.cfg.Filtering, items)
+	return m.list.SetItems(items)
 }
 
 func sortList(m model) func() tea.Msg {
 	return func() tea.Msg {
-		// reverse sorting order
-		if m.commands.config.Ordering == constants.AscendingOrdering {
+		// cycle through oldest, newest and highest scored first
+		switch m.commands.config.Ordering {
+		case constants.AscendingOrdering:
 			m.commands.config.Ordering = constants.DescendingOrdering
-		} else {
+		case constants.DescendingOrdering:
+			m.commands.config.Ordering = constants.ScoreOrdering
+		default:
 			m.commands.config.Ordering = constants.AscendingOrdering
 		}
 
@@ -106,11 +141,22 @@ func sortList(m model) func() tea.Msg {
 			m.errors = []string{err.Error()}
 		}
 		return listUpdate{
-			items: convertItems(items),
+			items:  convertItems(items),
+			status: "Sorted by " + orderingName(m.commands.config.Ordering),
 		}
 	}
 }
 
+func orderingName(ordering string) string {
+	switch ordering {
+	case constants.DescendingOrdering:
+		return "newest first"
+	case constants.ScoreOrdering:
+		return "score"
+	}
+	return "oldest first"
+}
+
 func refreshList(m model) func() tea.Msg {
 	return func() tea.Msg {
 		var errorItems []ErrorItem
@@ -167,11 +213,16 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case statusUpdate:
 		cmds = append(cmds, m.list.NewStatusMessage(msg.status))
+	case downloadUpdate:
+		cmds = append(cmds, m.list.NewStatusMessage(msg.status))
+		if !msg.done {
+			cmds = append(cmds, waitForDownload(msg.ch))
+		}
 	case listUpdate:
 		if m.list.SettingFilter() {
 			break
 		}
-		m.list.SetItems(msg.items)
+		m.setItems(msg.items)
 		cmds = append(cmds, m.list.NewStatusMessage(msg.status))
 
 	case tea.ResumeMsg:
@@ -180,6 +231,42 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		switch {
 		case key.Matches(msg, ListKeyMap.Suspend):
 			return m, tea.Suspend
+		case key.Matches(msg, ListKeyMap.ClearSelection) && len(m.selected) > 0 && !m.list.SettingFilter():
+			clear(m.selected)
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.Select):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			m.toggleSelected()
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.SelectRange):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			m.selectRange()
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.SelectAll):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			m.selectAll()
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.Undo), key.Matches(msg, ListKeyMap.Redo):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			status := m.undo(key.Matches(msg, ListKeyMap.Redo))
+			return m, tea.Batch(m.updateListKeepingCursor(), m.list.NewStatusMessage(status))
+
 		case key.Matches(msg, ListKeyMap.Refresh):
 			if m.list.SettingFilter() || m.list.IsFiltered() {
 				break
@@ -196,13 +283,19 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				return m, m.list.NewStatusMessage("No items to mark.")
 			}
 
+			if len(m.selected) > 0 {
+				return m, m.bulkRead()
+			}
+
 			item := m.list.SelectedItem()
 			if item == nil {
 				return m, m.list.NewStatusMessage("No item selected.")
 			}
 
 			current := item.(TUIItem)
-			err := m.commands.store.ToggleRead(current.ID)
+			err := m.record("read", []int{current.ID}, func() error {
+				return m.commands.toggleRead(current.ID)
+			})
 			if err != nil {
 				return m, tea.Quit
 			}
@@ -221,8 +314,28 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				break
 			}
 
-			m.commands.store.MarkAllRead()
-			cmds = append(cmds, m.UpdateList())
+			cmds = append(cmds, m.markShownRead())
+
+		case key.Matches(msg, ListKeyMap.YankURL), key.Matches(msg, ListKeyMap.YankTitle), key.Matches(msg, ListKeyMap.YankMarkdown):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			return m, m.yankList(listYankFormat(msg))
+
+		case key.Matches(msg, ListKeyMap.MarkFeedRead):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			return m, m.markFeedRead()
+
+		case key.Matches(msg, ListKeyMap.MarkAboveRead), key.Matches(msg, ListKeyMap.MarkBelowRead):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			return m, m.markAroundRead(key.Matches(msg, ListKeyMap.MarkAboveRead))
 
 		case key.Matches(msg, ListKeyMap.Favourite):
 			if m.list.SettingFilter() {
@@ -233,19 +346,154 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 				return m, m.list.NewStatusMessage("No items to favourite.")
 			}
 
+			if len(m.selected) > 0 {
+				return m, m.bulkFavourite()
+			}
+
 			item := m.list.SelectedItem()
 			if item == nil {
 				return m, m.list.NewStatusMessage("No item selected.")
 			}
 
 			current := item.(TUIItem)
-			err := m.commands.store.ToggleFavourite(current.ID)
+			err := m.record("favourite", []int{current.ID}, func() error {
+				return m.commands.store.ToggleFavourite(current.ID)
+			})
 			if err != nil {
 				return m, tea.Quit
 			}
 
 			cmds = append(cmds, m.UpdateList())
 
+		case key.Matches(msg, ListKeyMap.Tag):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			if len(m.selected) > 0 {
+				return m.openTagPicker(m.selectedIDs()...)
+			}
+
+			item := m.list.SelectedItem()
+			if item == nil {
+				return m, m.list.NewStatusMessage("No item selected.")
+			}
+
+			return m.openTagPicker(item.(TUIItem).ID)
+
+		case key.Matches(msg, ListKeyMap.ReadLater):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			item := m.list.SelectedItem()
+			if item == nil {
+				return m, m.list.NewStatusMessage("No item selected.")
+			}
+
+			cmds = append(cmds, m.toggleReadLater(item.(TUIItem).ID), m.UpdateList())
+
+		case key.Matches(msg, ListKeyMap.ToggleReadLater):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			if m.commands.config.ShowReadLater {
+				m.list.NewStatusMessage("")
+			} else {
+				m.list.NewStatusMessage("read later")
+			}
+
+			m.commands.config.ToggleShowReadLater()
+			cmds = append(cmds, m.UpdateList())
+
+		case key.Matches(msg, ListKeyMap.ToggleSidebar):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			cmds = append(cmds, m.toggleSidebar())
+
+		case key.Matches(msg, ListKeyMap.FocusSidebar):
+			if m.list.SettingFilter() || m.sidebar == nil {
+				break
+			}
+
+			m.sidebar.focused = true
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.TogglePreview):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			m.togglePreview()
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.FocusPreview):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			m.focusPreview()
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.GrowList), key.Matches(msg, ListKeyMap.ShrinkList):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			step := previewSizeStep
+			if key.Matches(msg, ListKeyMap.ShrinkList) {
+				step = -step
+			}
+			m.resizeList(step)
+			return m, nil
+
+		case key.Matches(msg, ListKeyMap.MoveUp), key.Matches(msg, ListKeyMap.MoveDown):
+			if m.list.SettingFilter() || m.list.IsFiltered() || !m.commands.config.ShowReadLater {
+				break
+			}
+
+			item := m.list.SelectedItem()
+			if item == nil {
+				return m, m.list.NewStatusMessage("No item selected.")
+			}
+
+			offset := 1
+			if key.Matches(msg, ListKeyMap.MoveUp) {
+				offset = -1
+			}
+
+			err := m.commands.MoveReadLater(item.(TUIItem).ID, offset)
+			if err != nil {
+				return m, m.list.NewStatusMessage(err.Error())
+			}
+
+			cmds = append(cmds, m.UpdateList())
+			m.list.Select(min(max(m.list.Index()+offset, 0), len(m.list.Items())-1))
+
+		case key.Matches(msg, ListKeyMap.SwitchView):
+			if m.list.SettingFilter() {
+				break
+			}
+
+			// the keys pick views in order, the last shows every item
+			keys := ListKeyMap.SwitchView.Keys()
+			n := slices.Index(keys, msg.String()) + 1
+			if n == len(keys) {
+				m.commands.config.ActiveView = ""
+				m.list.NewStatusMessage("")
+			} else if n > len(m.commands.config.Views) {
+				return m, m.list.NewStatusMessage(fmt.Sprintf("No view %d.", n))
+			} else {
+				m.commands.config.ActiveView = m.commands.config.Views[n-1].Name
+				m.list.NewStatusMessage("view: " + m.commands.config.ActiveView)
+			}
+
+			m.list.Select(0)
+			cmds = append(cmds, m.UpdateList())
+
 		case key.Matches(msg, ListKeyMap.ToggleFavourites):
 			if m.list.SettingFilter() {
 				break
@@ -260,7 +508,11 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			m.commands.config.ToggleShowFavourites()
 			cmds = append(cmds, m.UpdateList())
 
-		case key.Matches(msg, ViewportKeyMap.OpenInBrowser):
+		case key.Matches(msg, ListKeyMap.OpenInBrowser):
+			if len(m.selected) > 0 && !m.list.SettingFilter() {
+				return m, m.bulkOpen()
+			}
+
 			cmds = append(cmds, m.list.NewStatusMessage("Opening..."))
 			if m.list.SettingFilter() {
 				break
@@ -297,14 +549,14 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 
 				m.viewport.GotoTop()
 
-				content, err := m.commands.GetGlamourisedArticle(*m.selectedArticle)
+				content, err := m.openArticle(*m.selectedArticle)
 				if err != nil {
 					return m, tea.Quit
 				}
 
-				m.viewport.SetContent(content)
+				m.setArticle(content)
 
-				cmds = append(cmds, m.UpdateList())
+				cmds = append(cmds, m.UpdateList(), m.fetchImages(i.ID))
 			}
 
 		case key.Matches(msg, ListKeyMap.EditConfig):
@@ -337,13 +589,32 @@ func updateList(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 }
 
 func listView(m model) string {
+	var queryErr error
+	if m.list.SettingFilter() || m.list.IsFiltered() {
+		_, queryErr = query.Parse(m.list.FilterInput.Value(), query.Options{})
+	}
+
 	if len(m.errors) > 0 {
 		m.list.NewStatusMessage(m.errors[0])
+	} else if m.list.IsFiltered() && queryErr != nil {
+		m.list.NewStatusMessage(filterErrorStyle.Render("filter: " + queryErr.Error()))
+	} else if m.list.IsFiltered() && len(m.selected) > 0 {
+		m.list.NewStatusMessage(fmt.Sprintf("filtering: %s • %d selected", m.list.FilterInput.Value(), len(m.selected)))
 	} else if m.list.IsFiltered() {
 		m.list.NewStatusMessage("filtering: " + m.list.FilterInput.Value())
+	} else if len(m.selected) > 0 {
+		m.list.NewStatusMessage(fmt.Sprintf("%d selected", len(m.selected)))
+	}
+
+	view := m.list.View()
+
+	// show query errors next to the filter input while typing
+	if m.list.SettingFilter() && queryErr != nil {
+		bar, rest, _ := strings.Cut(view, "\n")
+		view = bar + "  " + filterErrorStyle.Render(queryErr.Error()) + "\n" + rest
 	}
 
-	return "\n" + m.list.View()
+	return "\n" + view
 }
 
 func getEditor(vars ...string) string {
@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// markReadWhere marks the unread items that match read, along with their
+// copies from other feeds
+func (c Commands) markReadWhere(match func(store.Item) bool) error {
+	if c.config.Dedupe.Enabled {
+		items, err := c.store.GetAllItems(c.config.Ordering)
+		if err != nil {
+			return fmt.Errorf("markReadWhere: %w", err)
+		}
+
+		clusters := make(map[int]bool)
+		for _, item := range items {
+			if item.Cluster != 0 && match(item) {
+				clusters[item.Cluster] = true
+			}
+		}
+
+		only := match
+		match = func(item store.Item) bool {
+			return only(item) || clusters[item.Cluster]
+		}
+	}
+
+	err := c.store.MarkReadWhere(match)
+	if err != nil {
+		return fmt.Errorf("markReadWhere: %w", err)
+	}
+
+	return nil
+}
+
+// feedsIn are the URLs of the feeds under a sidebar node
+func (c Commands) feedsIn(n *sidebarNode) map[string]bool {
+	feeds := make(map[string]bool)
+	for _, f := range c.config.GetFeeds() {
+		if n.isFeed() {
+			feeds[f.URL] = f.URL == n.feedURL
+		} else {
+			feeds[f.URL] = f.InGroup(n.group)
+		}
+	}
+
+	return feeds
+}
+
+// narrowed is whether the list shows only some items, so marking all read
+// should leave the rest alone
+func (m *model) narrowed() bool {
+	cfg := m.commands.config
+
+	return m.list.IsFiltered() || cfg.ActiveView != "" || cfg.ActiveFeed != "" ||
+		cfg.ActiveGroup != "" || cfg.ShowFavourites || cfg.ShowReadLater
+}
+
+// markShownRead marks every item read, or only those shown when the list is
+// narrowed by a filter, view or the sidebar
+func (m *model) markShownRead() tea.Cmd {
+	if !m.narrowed() {
+		err := m.record("mark all read", nil, m.commands.store.MarkAllRead)
+		if err != nil {
+			return m.list.NewStatusMessage(err.Error())
+		}
+
+		return m.updateListKeepingCursor()
+	}
+
+	var ids []int
+	for _, item := range m.list.VisibleItems() {
+		if it := item.(TUIItem); !it.Read {
+			ids = append(ids, it.ID)
+		}
+	}
+
+	return m.markIDsRead("mark shown read", ids)
+}
+
+// markAroundRead marks the shown items above or below the cursor read,
+// leaving the one under it
+func (m *model) markAroundRead(above bool) tea.Cmd {
+	items := m.list.VisibleItems()
+	cursor := m.list.Index()
+	if cursor >= len(items) {
+		return m.list.NewStatusMessage("No items to mark.")
+	}
+
+	name := "mark below read"
+	if above {
+		name = "mark above read"
+		items = items[:cursor]
+	} else {
+		items = items[cursor+1:]
+	}
+
+	var ids []int
+	for _, item := range items {
+		if it := item.(TUIItem); !it.Read {
+			ids = append(ids, it.ID)
+		}
+	}
+
+	return m.markIDsRead(name, ids)
+}
+
+func (m *model) markIDsRead(name string, ids []int) tea.Cmd {
+	if len(ids) == 0 {
+		return m.list.NewStatusMessage("Nothing to mark.")
+	}
+
+	err := m.record(name, ids, func() error {
+		return m.commands.setRead(ids, true)
+	})
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	return tea.Batch(m.updateListKeepingCursor(), m.list.NewStatusMessage(fmt.Sprintf("Marked %d read.", len(ids))))
+}
+
+// markFeedRead marks every item of the feed under the cursor read, shown or
+// not
+func (m *model) markFeedRead() tea.Cmd {
+	item, ok := m.list.SelectedItem().(TUIItem)
+	if !ok {
+		return m.list.NewStatusMessage("No item selected.")
+	}
+
+	current, err := m.commands.store.GetItemByID(item.ID)
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	err = m.record("mark feed read", nil, func() error {
+		return m.commands.markReadWhere(func(i store.Item) bool {
+			return i.FeedURL == current.FeedURL
+		})
+	})
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	return tea.Batch(m.updateListKeepingCursor(), m.list.NewStatusMessage(fmt.Sprintf("Marked %s read.", item.FeedName)))
+}
+
+// markNodeRead marks every item of the group or feed under the sidebar
+// cursor read
+func (m *model) markNodeRead(n *sidebarNode) tea.Cmd {
+	feeds := m.commands.feedsIn(n)
+
+	err := m.record("mark "+n.name+" read", nil, func() error {
+		return m.commands.markReadWhere(func(i store.Item) bool {
+			return n == m.sidebar.root || feeds[i.FeedURL]
+		})
+	})
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	return tea.Batch(m.updateListKeepingCursor(), m.list.NewStatusMessage(fmt.Sprintf("Marked %s read.", n.name)))
+}
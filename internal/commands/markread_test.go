@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+// markReadModel lists four items from one feed and one from another
+func markReadModel(t *testing.T) (model, store.Store) {
+	feeds := []config.Feed{{URL: "https://a.example/feed"}, {URL: "https://b.example/feed"}}
+
+	s, err := store.NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+	for _, title := range []string{"Go one", "Rust two", "Go three", "Rust four"} {
+		_, _, err := s.UpsertItem(store.Item{FeedURL: feeds[0].URL, Title: title})
+		test.HandleError(t, err)
+	}
+	_, _, err = s.UpsertItem(store.Item{FeedURL: feeds[1].URL, Title: "Zig five"})
+	test.HandleError(t, err)
+
+	cfg := &config.Config{ShowRead: true, Feeds: feeds}
+	m := model{
+		cfg:      cfg,
+		commands: New(cfg, s),
+		list:     list.New(nil, itemDelegate{}, 80, 20),
+		journal:  &journal{},
+	}
+	m.UpdateList()
+
+	return m, s
+}
+
+// readTitles are the titles of the items read
+func readTitles(t *testing.T, s store.Store) map[string]bool {
+	items, err := s.GetAllItems("")
+	test.HandleError(t, err)
+
+	read := make(map[string]bool)
+	for _, it := range items {
+		if it.Read() {
+			read[it.Title] = true
+		}
+	}
+
+	return read
+}
+
+// filterList filters the list as if text was typed after /
+func filterList(m *model, text string) {
+	// a blinking cursor would have its ticks waited on
+	m.list.FilterInput.Cursor.SetMode(cursor.CursorStatic)
+
+	keys := []tea.KeyMsg{{Type: tea.KeyRunes, Runes: []rune("/")}}
+	for _, r := range text {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	keys = append(keys, tea.KeyMsg{Type: tea.KeyEnter})
+
+	for _, k := range keys {
+		var cmd tea.Cmd
+		m.list, cmd = m.list.Update(k)
+		if cmd == nil {
+			continue
+		}
+		if matches, ok := cmd().(list.FilterMatchesMsg); ok {
+			m.list, _ = m.list.Update(matches)
+		}
+	}
+}
+
+func visibleTitles(m model) []string {
+	var titles []string
+	for _, item := range m.list.VisibleItems() {
+		titles = append(titles, item.(TUIItem).Title)
+	}
+
+	return titles
+}
+
+func TestMarkAroundReadEnds(t *testing.T) {
+	m, s := markReadModel(t)
+
+	m.list.Select(0)
+	m.markAroundRead(true)
+	test.Equal(t, 0, len(readTitles(t, s)), "nothing above the first item")
+
+	last := len(m.list.VisibleItems()) - 1
+	m.list.Select(last)
+	m.markAroundRead(false)
+	test.Equal(t, 0, len(readTitles(t, s)), "nothing below the last item")
+
+	m.markAroundRead(true)
+	read := readTitles(t, s)
+	test.Equal(t, 4, len(read), "everything above the last item")
+	test.Equal(t, false, read[visibleTitles(m)[last]], "item under the cursor left")
+	test.Equal(t, last, m.list.Index(), "cursor kept")
+}
+
+func TestMarkAroundReadFiltered(t *testing.T) {
+	m, s := markReadModel(t)
+
+	filterList(&m, "go")
+	shown := visibleTitles(m)
+	test.Equal(t, 2, len(shown), "filtered to the Go items")
+
+	m.list.Select(0)
+	m.markAroundRead(false)
+	read := readTitles(t, s)
+	test.Equal(t, 1, len(read), "only the shown item below")
+	test.Equal(t, true, read[shown[1]], "shown item below read")
+}
+
+func TestMarkShownRead(t *testing.T) {
+	m, s := markReadModel(t)
+
+	filterList(&m, "rust")
+	test.Equal(t, true, m.narrowed(), "narrowed by the filter")
+	m.markShownRead()
+	read := readTitles(t, s)
+	test.Equal(t, 2, len(read), "only the shown items")
+	test.Equal(t, true, read["Rust two"] && read["Rust four"], "shown items read")
+
+	m, s = markReadModel(t)
+	test.Equal(t, false, m.narrowed(), "whole list")
+	m.markShownRead()
+	test.Equal(t, 5, len(readTitles(t, s)), "every item")
+
+	m.undo(false)
+	test.Equal(t, 0, len(readTitles(t, s)), "all read undone")
+}
+
+func TestMarkFeedRead(t *testing.T) {
+	m, s := markReadModel(t)
+
+	// the feed's items filtered out are read too
+	filterList(&m, "go")
+	m.list.Select(0)
+	m.markFeedRead()
+
+	read := readTitles(t, s)
+	test.Equal(t, 4, len(read), "every item of the feed")
+	test.Equal(t, false, read["Zig five"], "other feed left")
+}
@@ -0,0 +1,349 @@
+package commands
+
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// the app's top padding, the blank line listView starts with and the
+	// list's title bar sit above the first item
+	listItemsTop = 4
+	// the app's top padding, and the sidebar's title and a blank line
+	sidebarRowsTop = 3
+	// the border, title, blank line, input and another blank line sit above
+	// the first link in the picker
+	linkPickerOptionsTop = 5
+)
+
+// keyTypes finds keys by their names in bindings, such as "enter" or "ctrl+r"
+var keyTypes = func() map[string]tea.KeyType {
+	types := make(map[string]tea.KeyType)
+	for t := tea.KeyType(-200); t < 128; t++ {
+		if name := (tea.Key{Type: t}).String(); name != "" && t != tea.KeyRunes {
+			types[name] = t
+		}
+	}
+	return types
+}()
+
+// keyMsgFor is the key press that triggers a binding, so clicking it in the
+// help does what the key does
+func keyMsgFor(b key.Binding) (tea.KeyMsg, bool) {
+	if len(b.Keys()) == 0 {
+		return tea.KeyMsg{}, false
+	}
+
+	name := b.Keys()[0]
+	alt := false
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok && rest != "" {
+		name, alt = rest, true
+	}
+
+	if t, ok := keyTypes[name]; ok {
+		return tea.KeyMsg{Type: t, Alt: alt}, true
+	}
+
+	if r := []rune(name); len(r) == 1 {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: r, Alt: alt}, true
+	}
+
+	return tea.KeyMsg{}, false
+}
+
+// helpBindingAt is the binding shown at column x of the short help, laid out
+// as help.ShortHelpView does
+func helpBindingAt(h help.Model, bindings []key.Binding, x int) (key.Binding, bool) {
+	sepWidth := lipgloss.Width(h.ShortSeparator)
+
+	pos := 0
+	for _, b := range bindings {
+		if !b.Enabled() {
+			continue
+		}
+
+		start := pos
+		if pos > 0 {
+			start += sepWidth
+		}
+		end := start + lipgloss.Width(b.Help().Key) + 1 + lipgloss.Width(b.Help().Desc)
+		if h.Width > 0 && end > h.Width {
+			break
+		}
+
+		if x >= start && x < end {
+			return b, true
+		}
+		pos = end
+	}
+
+	return key.Binding{}, false
+}
+
+// helpClicked finds the short help entry under a click, by where the help
+// sits in the clicked line of the screen
+func helpClicked(line string, h help.Model, bindings []key.Binding, x int) (key.Binding, bool) {
+	line = plainLine(line)
+	shown := plainLine(h.ShortHelpView(bindings))
+	if shown == "" {
+		return key.Binding{}, false
+	}
+
+	i := strings.Index(line, shown)
+	if i < 0 {
+		return key.Binding{}, false
+	}
+
+	return helpBindingAt(h, bindings, x-lipgloss.Width(line[:i]))
+}
+
+// centred is where something size long starts when centred in outer, as
+// lipgloss.Place puts it
+func centred(outer, size int) int {
+	gap := outer - size
+	if gap <= 0 {
+		return 0
+	}
+
+	return gap - int(math.Round(float64(gap)*0.5))
+}
+
+// screen is the view as the terminal shows it, less any lines at the top
+// that don't fit, and how many lines those are
+func (m model) screen() ([]string, int) {
+	lines := strings.Split(m.View(), "\n")
+	_, y := appStyle.GetFrameSize()
+
+	offset := max(len(lines)-(m.height+y), 0)
+
+	return lines[offset:], offset
+}
+
+// clickHelp presses the key of the help entry under a click
+func (m model) clickHelp(msg tea.MouseMsg, h help.Model, bindings []key.Binding) (tea.Model, tea.Cmd, bool) {
+	lines, _ := m.screen()
+	if msg.Y < 0 || msg.Y >= len(lines) {
+		return m, nil, false
+	}
+
+	b, ok := helpClicked(lines[msg.Y], h, bindings, msg.X)
+	if !ok {
+		return m, nil, false
+	}
+
+	keyMsg, ok := keyMsgFor(b)
+	if !ok {
+		return m, nil, false
+	}
+
+	next, cmd := m.Update(keyMsg)
+	return next, cmd, true
+}
+
+func updateMouse(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	switch {
+	case m.tagPicker != nil:
+		return m, nil
+	case m.linkPicker != nil:
+		return mouseLinkPicker(msg, m)
+	case m.selectedArticle != nil:
+		return mouseViewport(msg, m)
+	default:
+		return mouseMain(msg, m)
+	}
+}
+
+// mouseLinkPicker opens the clicked link, and closes the picker on a click
+// outside it
+func mouseLinkPicker(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return m, nil
+	}
+
+	p := m.linkPicker
+	box := linkPickerBox(m)
+	_, offset := m.screen()
+	appTop, _, _, _ := appStyle.GetPadding()
+
+	top := appTop + centred(m.height, lipgloss.Height(box)) - offset
+	left := centred(m.width, lipgloss.Width(box))
+	if msg.Y < top || msg.Y >= top+lipgloss.Height(box) || msg.X < left || msg.X >= left+lipgloss.Width(box) {
+		m.linkPicker = nil
+		return m, nil
+	}
+
+	opts := p.options()
+	i := p.start() + msg.Y - top - linkPickerOptionsTop
+	if msg.Y-top < linkPickerOptionsTop || i >= min(len(opts), p.start()+linkPickerHeight) {
+		return m, nil
+	}
+
+	m.linkPicker = nil
+	m.status = "Opening..."
+	return m, m.OpenLink(opts[i].url)
+}
+
+// mouseViewport scrolls the article with the wheel, and presses the keys
+// clicked in the help
+func mouseViewport(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	if tea.MouseEvent(msg).IsWheel() {
+		var cmd tea.Cmd
+		m.viewport, cmd = m.viewport.Update(msg)
+		return m, cmd
+	}
+
+	if msg.Button != tea.MouseButtonLeft || m.search != nil && m.search.typing || m.help.ShowAll {
+		return m, nil
+	}
+
+	next, cmd, _ := m.clickHelp(msg, m.help, ViewportKeyMap.ShortHelp())
+	return next, cmd
+}
+
+// mouseMain handles the list and the panes beside it. The wheel scrolls
+// whatever is under it, and clicking an item opens it.
+func mouseMain(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	if msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+
+	_, offset := m.screen()
+	y := msg.Y + offset
+
+	x := msg.X
+	if m.sidebar != nil {
+		if x < sidebarWidth+1 {
+			return mouseSidebar(msg, y, m)
+		}
+		x -= sidebarWidth + 1
+	}
+
+	if m.previewShown() {
+		inPreview := x >= m.list.Width()
+		if m.preview.bottom {
+			inPreview = y >= listItemsTop-2+m.list.Height()
+		}
+		if inPreview {
+			return mousePreview(msg, m)
+		}
+	}
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		m.list.CursorUp()
+		m.syncPreview()
+		return m, nil
+
+	case tea.MouseButtonWheelDown:
+		m.list.CursorDown()
+		m.syncPreview()
+		return m, nil
+
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	if m.list.SettingFilter() {
+		return m, nil
+	}
+
+	// the keys go to the list from here on, as they do leaving the preview
+	var cmd tea.Cmd
+	if m.sidebar != nil {
+		m.sidebar.focused = false
+	}
+	if m.preview != nil && m.preview.focused {
+		m.preview.focused = false
+		cmd = m.UpdateList()
+	}
+
+	if !m.list.Help.ShowAll {
+		if next, helpCmd, ok := m.clickHelp(msg, m.list.Help, m.list.ShortHelp()); ok {
+			return next, tea.Batch(cmd, helpCmd)
+		}
+	}
+
+	row := y - listItemsTop
+	i := m.list.Paginator.Page*m.list.Paginator.PerPage + row
+	if row < 0 || row >= m.list.Paginator.PerPage || i >= len(m.list.VisibleItems()) {
+		return m, cmd
+	}
+
+	keyMsg, ok := keyMsgFor(ListKeyMap.Open)
+	if !ok {
+		return m, cmd
+	}
+
+	m.list.Select(i)
+	next, openCmd := m.Update(keyMsg)
+
+	return next, tea.Batch(cmd, openCmd)
+}
+
+// mouseSidebar moves through the sidebar with the wheel, and picks the
+// clicked feed or group
+func mouseSidebar(msg tea.MouseMsg, y int, m model) (tea.Model, tea.Cmd) {
+	s := m.sidebar
+	rows := s.rows()
+
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		s.cursor = max(s.cursor-1, 0)
+		return m, nil
+
+	case tea.MouseButtonWheelDown:
+		s.cursor = min(s.cursor+1, len(rows)-1)
+		return m, nil
+
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+
+	height := max(m.height-3, 1)
+	i := max(0, s.cursor-height+1) + y - sidebarRowsTop
+	if y < sidebarRowsTop || i >= len(rows) {
+		return m, nil
+	}
+
+	s.cursor = i
+	s.focused = true
+	if m.preview != nil {
+		m.preview.focused = false
+	}
+
+	keyMsg, ok := keyMsgFor(SidebarKeyMap.Select)
+	if !ok {
+		return m, nil
+	}
+
+	return m.Update(keyMsg)
+}
+
+// mousePreview scrolls the preview with the wheel, and a click focuses it
+func mousePreview(msg tea.MouseMsg, m model) (tea.Model, tea.Cmd) {
+	switch {
+	case tea.MouseEvent(msg).IsWheel():
+		var cmd tea.Cmd
+		m.preview.viewport, cmd = m.preview.viewport.Update(msg)
+		return m, cmd
+
+	case msg.Button == tea.MouseButtonLeft:
+		if m.sidebar != nil {
+			m.sidebar.focused = false
+		}
+		m.focusPreview()
+	}
+
+	return m, nil
+}
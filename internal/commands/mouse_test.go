@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestKeyMsgFor(t *testing.T) {
+	for _, k := range []string{"enter", "esc", " ", "ctrl+r", "alt+m", "alt+y", "G", "?", "pgdown", "shift+tab"} {
+		msg, ok := keyMsgFor(key.NewBinding(key.WithKeys(k)))
+		test.Equal(t, true, ok, k+" should have a key press")
+		test.Equal(t, k, msg.String(), "the key press should match the binding")
+	}
+
+	_, ok := keyMsgFor(key.NewBinding())
+	test.Equal(t, false, ok, "a binding without keys can't be pressed")
+}
+
+func TestHelpClicked(t *testing.T) {
+	h := help.New()
+	bindings := []key.Binding{
+		key.NewBinding(key.WithKeys("j"), key.WithHelp("j", "down")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "hidden"), key.WithDisabled()),
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open")),
+	}
+
+	// "    status  j down • enter open"
+	line := "    status  " + h.ShortHelpView(bindings)
+
+	b, ok := helpClicked(line, h, bindings, 13)
+	test.Equal(t, true, ok, "clicking an entry should find it")
+	test.Equal(t, "j", b.Help().Key, "the entry under the click")
+
+	b, _ = helpClicked(line, h, bindings, 30)
+	test.Equal(t, "enter", b.Help().Key, "disabled entries aren't shown")
+
+	_, ok = helpClicked(line, h, bindings, 19)
+	test.Equal(t, false, ok, "the separator isn't an entry")
+
+	_, ok = helpClicked("    status", h, bindings, 5)
+	test.Equal(t, false, ok, "a line without the help")
+
+}
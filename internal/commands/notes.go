@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+type noteSaved struct {
+	err error
+}
+
+// editNote opens the item's note in the user's editor, saving it once the
+// editor exits.
+func (m model) editNote(ID int) tea.Cmd {
+	item, err := m.commands.store.GetItemByID(ID)
+	if err != nil {
+		return statusCmd(err.Error())
+	}
+
+	f, err := os.CreateTemp("", fmt.Sprintf("nom-note-%d-*.md", ID))
+	if err != nil {
+		return statusCmd(err.Error())
+	}
+
+	_, err = f.WriteString(item.Note)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return statusCmd(err.Error())
+	}
+
+	cmd := strings.Split(getEditor("NOMEDITOR", "VISUAL", "EDITOR"), " ")
+	cmd = append(cmd, f.Name())
+
+	execCmd := exec.Command(cmd[0], cmd[1:]...)
+	s := m.commands.store
+	return tea.ExecProcess(execCmd, func(err error) tea.Msg {
+		defer os.Remove(f.Name())
+
+		if err != nil {
+			return noteSaved{err: err}
+		}
+
+		note, err := os.ReadFile(f.Name())
+		if err != nil {
+			return noteSaved{err: err}
+		}
+
+		return noteSaved{err: s.SetNote(ID, strings.TrimSpace(string(note)))}
+	})
+}
+
+// ExportFavourites writes favourites and their notes to stdout as markdown
+func (c Commands) ExportFavourites() error {
+	its, err := c.store.GetAllItems(c.config.Ordering)
+	if err != nil {
+		return fmt.Errorf("commands ExportFavourites: %w", err)
+	}
+
+	fmt.Print(formatFavourites(onlyFavourites(its)))
+
+	return nil
+}
+
+func formatFavourites(items []store.Item) string {
+	var out strings.Builder
+
+	out.WriteString("# Favourites\n")
+	for _, item := range items {
+		out.WriteString("\n## " + item.Title + "\n\n")
+		// listed, as lines on their own would run into one paragraph
+		if item.FeedName != "" {
+			out.WriteString("- " + item.FeedName + "\n")
+		}
+		if item.Link != "" {
+			out.WriteString("- " + item.Link + "\n")
+		}
+		if item.Note != "" {
+			out.WriteString("\n" + item.Note + "\n")
+		}
+	}
+
+	return out.String()
+}
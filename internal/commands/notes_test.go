@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFormatFavourites(t *testing.T) {
+	items := []store.Item{
+		{Title: "First", FeedName: "blog", Link: "https://example.com/1", Note: "Discuss on Monday."},
+		{Title: "Second", Link: "https://example.com/2"},
+	}
+
+	want := `# Favourites
+
+## First
+
+- blog
+- https://example.com/1
+
+Discuss on Monday.
+
+## Second
+
+- https://example.com/2
+`
+
+	test.Equal(t, want, formatFavourites(items), "bad export")
+}
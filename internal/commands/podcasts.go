@@ -0,0 +1,214 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/rss"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// how often download progress is reported to the TUI
+const downloadProgressInterval = 250 * time.Millisecond
+
+type downloadUpdate struct {
+	status string
+	done   bool
+	ch     chan downloadUpdate
+}
+
+// DownloadEnclosure downloads an enclosure into the podcast directory in the
+// background, reporting progress as downloadUpdate messages.
+func (m model) DownloadEnclosure(e store.Enclosure) tea.Cmd {
+	ch := make(chan downloadUpdate)
+	dir := m.cfg.GetPodcastDir()
+	cfg := m.cfg
+
+	go func() {
+		defer close(ch)
+
+		dest, err := downloadEnclosure(e.URL, dir, cfg, func(written, total int64) {
+			ch <- downloadUpdate{status: formatProgress(written, total)}
+		})
+		if err != nil {
+			ch <- downloadUpdate{status: err.Error(), done: true}
+			return
+		}
+
+		ch <- downloadUpdate{status: "Downloaded " + dest, done: true}
+	}()
+
+	return waitForDownload(ch)
+}
+
+func waitForDownload(ch chan downloadUpdate) tea.Cmd {
+	return func() tea.Msg {
+		u, ok := <-ch
+		if !ok {
+			return nil
+		}
+		u.ch = ch
+		return u
+	}
+}
+
+// downloadEnclosure fetches rawURL into dir and returns the written path. The
+// file is written under a temporary name so partial downloads are never
+// mistaken for complete ones.
+func downloadEnclosure(rawURL string, dir string, cfg *config.Config, progress func(written, total int64)) (string, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", fmt.Errorf("downloadEnclosure: %w", err)
+	}
+
+	dest := filepath.Join(dir, enclosureFileName(rawURL))
+
+	body, length, err := rss.FetchEnclosure(rawURL, cfg.HTTPOptions, cfg.Version)
+	if err != nil {
+		return "", fmt.Errorf("downloadEnclosure: %w", err)
+	}
+	defer body.Close()
+
+	f, err := os.Create(dest + ".part")
+	if err != nil {
+		return "", fmt.Errorf("downloadEnclosure: %w", err)
+	}
+
+	pw := &progressWriter{total: length, report: progress}
+	_, err = io.Copy(io.MultiWriter(f, pw), body)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(dest + ".part")
+		return "", fmt.Errorf("downloadEnclosure: %w", err)
+	}
+
+	err = os.Rename(dest+".part", dest)
+	if err != nil {
+		return "", fmt.Errorf("downloadEnclosure: %w", err)
+	}
+
+	return dest, nil
+}
+
+func enclosureFileName(rawURL string) string {
+	name := ""
+	if u, err := url.Parse(rawURL); err == nil {
+		name = path.Base(u.Path)
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, name)
+
+	if name == "" || name == "." || name == "/" {
+		name = fmt.Sprintf("enclosure-%d", time.Now().Unix())
+	}
+
+	return name
+}
+
+type progressWriter struct {
+	written  int64
+	total    int64
+	reported time.Time
+	report   func(written, total int64)
+}
+
+func (p *progressWriter) Write(b []byte) (int, error) {
+	p.written += int64(len(b))
+
+	if p.report != nil && time.Since(p.reported) >= downloadProgressInterval {
+		p.reported = time.Now()
+		p.report(p.written, p.total)
+	}
+
+	return len(b), nil
+}
+
+func formatProgress(written, total int64) string {
+	if total <= 0 {
+		return fmt.Sprintf("Downloading... %s", humanBytes(written))
+	}
+
+	return fmt.Sprintf("Downloading... %d%% of %s", written*100/total, humanBytes(total))
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func formatEpisode(it store.ITunes) string {
+	var parts []string
+
+	if it.Season != "" {
+		parts = append(parts, "Season "+it.Season)
+	}
+	if it.Episode != "" {
+		parts = append(parts, "Episode "+it.Episode)
+	}
+	if it.Duration != "" {
+		parts = append(parts, it.Duration)
+	}
+
+	s := strings.Join(parts, " · ")
+	if it.Subtitle != "" {
+		if s != "" {
+			s += "\n"
+		}
+		s += it.Subtitle
+	}
+
+	return s
+}
+
+func formatEnclosures(enclosures []store.Enclosure) string {
+	var out strings.Builder
+
+	out.WriteString("## Enclosures\n\n")
+	for _, e := range enclosures {
+		details := []string{}
+		if e.Type != "" {
+			details = append(details, e.Type)
+		}
+		if e.Duration != "" {
+			details = append(details, e.Duration)
+		}
+		if e.Length > 0 {
+			details = append(details, humanBytes(e.Length))
+		}
+
+		out.WriteString("- " + e.URL)
+		if len(details) > 0 {
+			out.WriteString(" (" + strings.Join(details, ", ") + ")")
+		}
+		out.WriteString("\n")
+	}
+
+	return out.String()
+}
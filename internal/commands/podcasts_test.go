@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestDownloadEnclosure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/media/episode-1.mp3" || r.UserAgent() != "nom/test" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("not really audio"))
+	}))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "podcasts")
+	cfg := &config.Config{Version: "test"}
+
+	dest, err := downloadEnclosure(srv.URL+"/media/episode-1.mp3?token=abc", dir, cfg, nil)
+	test.HandleError(t, err)
+	test.Equal(t, filepath.Join(dir, "episode-1.mp3"), dest, "bad destination")
+
+	b, err := os.ReadFile(dest)
+	test.HandleError(t, err)
+	test.Equal(t, "not really audio", string(b), "bad download")
+
+	_, err = os.Stat(dest + ".part")
+	test.Equal(t, true, os.IsNotExist(err), "partial file left behind")
+
+	_, err = downloadEnclosure(srv.URL+"/missing.mp3", dir, cfg, nil)
+	if err == nil {
+		t.Fatal("expected an error for a missing enclosure")
+	}
+}
+
+func TestHumanBytes(t *testing.T) {
+	test.Equal(t, "512 B", humanBytes(512), "bad bytes")
+	test.Equal(t, "1.5 KB", humanBytes(1536), "bad kilobytes")
+	test.Equal(t, "33.0 MB", humanBytes(34567890), "bad megabytes")
+}
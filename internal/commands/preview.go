@@ -0,0 +1,266 @@
+package commands
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+const (
+	defaultPreviewSize     = 50
+	defaultPreviewMinWidth = 100
+	// the list keeps between these percentages of the screen when resized
+	minPreviewSize  = 20
+	maxPreviewSize  = 80
+	previewSizeStep = 5
+)
+
+// preview shows the item under the list cursor in a pane beside or below
+// the list
+type preview struct {
+	viewport viewport.Model
+	// id is the item shown, 0 when the content needs rendering again
+	id      int
+	size    int
+	bottom  bool
+	focused bool
+}
+
+func newPreview(cfg config.PreviewConfig) *preview {
+	size := cfg.Size
+	if size == 0 {
+		size = defaultPreviewSize
+	}
+
+	vp := viewport.New(0, 0)
+	vp.KeyMap = ViewportKeyMap.ViewportKeys()
+
+	return &preview{
+		viewport: vp,
+		size:     min(max(size, minPreviewSize), maxPreviewSize),
+		bottom:   cfg.Position == config.PreviewBottom,
+	}
+}
+
+// previewShown is whether there is a preview pane, and room for it
+func (m *model) previewShown() bool {
+	if m.preview == nil {
+		return false
+	}
+	if m.preview.bottom {
+		return true
+	}
+
+	minWidth := m.commands.config.Preview.MinWidth
+	if minWidth == 0 {
+		minWidth = defaultPreviewMinWidth
+	}
+
+	return m.width >= minWidth
+}
+
+// resizePreview gives the preview what the list doesn't use of width and
+// height, returning the list's size
+func (m *model) resizePreview(width, height int) (int, int) {
+	p := m.preview
+	oldWidth := p.viewport.Width
+
+	if p.bottom {
+		listHeight := height * p.size / 100
+		p.viewport.Width = width
+		// less the border above the pane
+		p.viewport.Height = max(height-listHeight-1, 1)
+		height = listHeight
+	} else {
+		listWidth := width * p.size / 100
+		p.viewport.Width = max(width-listWidth-1, 1)
+		p.viewport.Height = height
+		width = listWidth
+	}
+
+	// content is wrapped to the pane, so render it again at the new width
+	if p.viewport.Width != oldWidth {
+		p.id = 0
+	}
+
+	return width, height
+}
+
+// syncPreview renders the item under the list cursor in the preview
+func (m *model) syncPreview() {
+	if !m.previewShown() {
+		return
+	}
+
+	id := 0
+	if item, ok := m.list.SelectedItem().(TUIItem); ok {
+		id = item.ID
+	}
+	if id == m.preview.id {
+		return
+	}
+
+	m.preview.id = id
+	m.preview.viewport.GotoTop()
+	if id == 0 {
+		m.preview.viewport.SetContent("")
+		return
+	}
+
+	m.renderPreview()
+}
+
+func (m *model) renderPreview() {
+	// leave room for glamour's margin
+	content, err := m.commands.GetPreviewArticle(m.preview.id, max(m.preview.viewport.Width-2, 10))
+	if err != nil {
+		content = err.Error()
+	}
+
+	m.preview.viewport.SetContent(content)
+}
+
+func (m *model) togglePreview() {
+	if m.preview != nil {
+		m.preview = nil
+	} else {
+		m.preview = newPreview(m.commands.config.Preview)
+	}
+
+	m.resize()
+	m.syncPreview()
+}
+
+// resizeList grows or shrinks the list's share of the screen by step percent
+func (m *model) resizeList(step int) {
+	if !m.previewShown() {
+		return
+	}
+
+	m.preview.size = min(max(m.preview.size+step, minPreviewSize), maxPreviewSize)
+	m.resize()
+	m.syncPreview()
+}
+
+// focusPreview moves the keys to the preview pane. Reading the article there
+// counts as opening it, so autoread marks it read, but the list is left alone
+// until focus comes back so the article doesn't vanish from under the cursor.
+func (m *model) focusPreview() {
+	if !m.previewShown() || m.preview.id == 0 {
+		return
+	}
+
+	m.preview.focused = true
+
+	if !m.commands.config.AutoRead {
+		return
+	}
+
+	item, err := m.commands.store.GetItemByID(m.preview.id)
+	if err != nil || item.Read() {
+		return
+	}
+
+	err = m.record("read", []int{item.ID}, func() error {
+		return m.commands.toggleRead(item.ID)
+	})
+	if err != nil {
+		m.list.NewStatusMessage(err.Error())
+		return
+	}
+
+	m.renderPreview()
+}
+
+func updatePreview(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return updateList(msg, m)
+	}
+
+	switch {
+	case key.Matches(keyMsg, PreviewKeyMap.Quit):
+		return m, tea.Quit
+
+	case key.Matches(keyMsg, PreviewKeyMap.Blur):
+		m.preview.focused = false
+		return m, m.UpdateList()
+
+	case key.Matches(keyMsg, PreviewKeyMap.Open):
+		m.preview.focused = false
+		id := m.preview.id
+		m.selectedArticle = &id
+
+		content, err := m.openArticle(id)
+		if err != nil {
+			return m, tea.Quit
+		}
+
+		m.setArticle(content)
+		m.viewport.GotoTop()
+		return m, tea.Batch(m.UpdateList(), m.fetchImages(id))
+
+	case key.Matches(keyMsg, PreviewKeyMap.OpenInBrowser):
+		item, err := m.commands.store.GetItemByID(m.preview.id)
+		if err != nil {
+			return m, nil
+		}
+
+		return m, m.OpenLink(item.Link)
+
+	case key.Matches(keyMsg, PreviewKeyMap.GotoStart):
+		m.preview.viewport.GotoTop()
+		return m, nil
+
+	case key.Matches(keyMsg, PreviewKeyMap.GotoEnd):
+		m.preview.viewport.GotoBottom()
+		return m, nil
+	}
+
+	m.preview.viewport, cmd = m.preview.viewport.Update(msg)
+
+	return m, cmd
+}
+
+func previewView(m model) string {
+	color := lipgloss.Color("240")
+	if m.preview.focused {
+		color = lipgloss.Color(m.cfg.Theme.SelectedItemColor)
+	}
+
+	style := lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(color)
+	// clip long links that glamour can't wrap
+	if m.preview.bottom {
+		style = style.BorderTop(true).MaxWidth(m.preview.viewport.Width)
+	} else {
+		style = style.BorderLeft(true).MaxWidth(m.preview.viewport.Width + 1)
+	}
+
+	return style.Render(m.preview.viewport.View())
+}
+
+// mainView lays out the list with the preview and sidebar panes
+func mainView(m model) string {
+	s := listView(m)
+
+	if m.previewShown() {
+		if m.preview.bottom {
+			s = lipgloss.JoinVertical(lipgloss.Left, s, previewView(m))
+		} else {
+			s = lipgloss.JoinHorizontal(lipgloss.Top, s, previewView(m))
+		}
+	}
+
+	if m.sidebar != nil {
+		s = lipgloss.JoinHorizontal(lipgloss.Top, sidebarView(m), s)
+	}
+
+	return s
+}
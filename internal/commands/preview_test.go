@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestResizePreview(t *testing.T) {
+	m := model{preview: newPreview(config.PreviewConfig{Size: 60})}
+	width, height := m.resizePreview(100, 40)
+	test.Equal(t, 60, width, "list width")
+	test.Equal(t, 40, height, "list height")
+	test.Equal(t, 39, m.preview.viewport.Width, "preview width less its border")
+	test.Equal(t, 40, m.preview.viewport.Height, "preview height")
+
+	m = model{preview: newPreview(config.PreviewConfig{Position: config.PreviewBottom})}
+	width, height = m.resizePreview(100, 40)
+	test.Equal(t, 100, width, "bottom list width")
+	test.Equal(t, 20, height, "bottom list height")
+	test.Equal(t, 19, m.preview.viewport.Height, "bottom preview height less its border")
+
+	// sizes are kept to what leaves both panes usable
+	test.Equal(t, maxPreviewSize, newPreview(config.PreviewConfig{Size: 95}).size, "size clamped")
+}
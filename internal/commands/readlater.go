@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ReadLater prints the read later queue in order
+func (c Commands) ReadLater() error {
+	its, err := c.store.GetReadLaterItems()
+	if err != nil {
+		return fmt.Errorf("commands ReadLater: %w", err)
+	}
+
+	output := ""
+
+	for _, item := range its {
+		output += fmt.Sprintf("%d. %s \n  - %s\n", item.ID, item.Title, item.Link)
+	}
+
+	if c.config.Pager == "false" {
+		fmt.Println(output)
+		return nil
+	}
+
+	return outputToPager(output)
+}
+
+// AddToReadLater queues an item, storing its full text first if configured
+func (c Commands) AddToReadLater(ID int) error {
+	err := c.store.AddToReadLater(ID)
+	if err != nil {
+		return fmt.Errorf("commands AddToReadLater: %w", err)
+	}
+
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("commands AddToReadLater: %w", err)
+	}
+
+	if c.config.ReadLater.FetchFullText && isWebLink(item.Link) {
+		err = c.storeFullText(ID)
+		if err != nil {
+			return fmt.Errorf("commands AddToReadLater: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (c Commands) RemoveFromReadLater(ID int) error {
+	err := c.store.RemoveFromReadLater(ID)
+	if err != nil {
+		return fmt.Errorf("commands RemoveFromReadLater: %w", err)
+	}
+
+	return nil
+}
+
+func (c Commands) MoveReadLater(ID int, offset int) error {
+	err := c.store.MoveReadLater(ID, offset)
+	if err != nil {
+		return fmt.Errorf("commands MoveReadLater: %w", err)
+	}
+
+	return nil
+}
+
+// toggleReadLater adds or removes an item from the queue. The full text is
+// fetched in the background so the TUI doesn't block on the network.
+func (m model) toggleReadLater(ID int) tea.Cmd {
+	item, err := m.commands.store.GetItemByID(ID)
+	if err != nil {
+		return statusCmd(err.Error())
+	}
+
+	if item.ReadLater {
+		if err := m.commands.RemoveFromReadLater(ID); err != nil {
+			return statusCmd(err.Error())
+		}
+		return statusCmd("Removed from read later.")
+	}
+
+	if err := m.commands.store.AddToReadLater(ID); err != nil {
+		return statusCmd(err.Error())
+	}
+
+	if !m.cfg.ReadLater.FetchFullText || !isWebLink(item.Link) {
+		return statusCmd("Saved for later.")
+	}
+
+	c := m.commands
+	return tea.Batch(statusCmd("Saved for later, fetching full text..."), func() tea.Msg {
+		if err := c.storeFullText(ID); err != nil {
+			return statusUpdate{status: err.Error()}
+		}
+		return statusUpdate{status: "Saved for later with full text."}
+	})
+}
+
+func statusCmd(status string) tea.Cmd {
+	return func() tea.Msg {
+		return statusUpdate{status: status}
+	}
+}
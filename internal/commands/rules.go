@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os/exec"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// hookTimeout is how long a rule's hook may run before it is killed, so a
+// hung hook can't hold up the refresh
+var hookTimeout = 30 * time.Second
+
+// rule is a config rule with its regexes compiled
+type rule struct {
+	config.Rule
+	title   *regexp.Regexp
+	author  *regexp.Regexp
+	content *regexp.Regexp
+}
+
+func compileRules(rules []config.Rule) ([]rule, error) {
+	var rs []rule
+	for _, r := range rules {
+		title, author, content, err := r.Regexes()
+		if err != nil {
+			return nil, fmt.Errorf("compileRules: %w", err)
+		}
+		rs = append(rs, rule{Rule: r, title: title, author: author, content: content})
+	}
+
+	return rs, nil
+}
+
+func (r rule) matches(item store.Item) bool {
+	m := r.Match
+
+	if !config.MatchFeed(m.Feeds, item.FeedName, item.FeedURL) {
+		return false
+	}
+
+	if !config.MatchAny(m.Categories, item.Categories) {
+		return false
+	}
+
+	if r.title != nil && !r.title.MatchString(item.Title) {
+		return false
+	}
+	if r.author != nil && !r.author.MatchString(item.Author) {
+		return false
+	}
+	if r.content != nil && !r.content.MatchString(item.Content) {
+		return false
+	}
+
+	return true
+}
+
+func matchingRules(rules []rule, item store.Item) []rule {
+	var ms []rule
+	for _, r := range rules {
+		if r.matches(item) {
+			ms = append(ms, r)
+		}
+	}
+
+	return ms
+}
+
+func deletes(rules []rule) bool {
+	return slices.ContainsFunc(rules, func(r rule) bool { return r.Actions.Delete })
+}
+
+// applyRules carries out the actions of the rules matching a newly stored
+// item. New items are unread and not favourites, so toggling sets them.
+func (c Commands) applyRules(ID int, item store.Item, rules []rule) error {
+	var read, favourite bool
+	for _, r := range rules {
+		read = read || r.Actions.Read
+		favourite = favourite || r.Actions.Favourite
+	}
+
+	if read {
+		if err := c.store.ToggleRead(ID); err != nil {
+			return fmt.Errorf("applyRules: %w", err)
+		}
+	}
+
+	if favourite {
+		if err := c.store.ToggleFavourite(ID); err != nil {
+			return fmt.Errorf("applyRules: %w", err)
+		}
+	}
+
+	for _, r := range rules {
+		for _, tag := range r.Actions.Tags {
+			if err := c.store.AddTag(ID, tag); err != nil {
+				return fmt.Errorf("applyRules: %w", err)
+			}
+		}
+
+		if r.Actions.Hook != "" {
+			runHook(r, item)
+		}
+	}
+
+	return nil
+}
+
+// runHook runs a rule's hook, logging failures so one bad hook doesn't
+// stop a refresh
+func runHook(r rule, item store.Item) {
+	parts := strings.Fields(strings.ReplaceAll(r.Actions.Hook, "%s", item.Link))
+	if len(parts) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
+	// children left holding its output aren't waited on either
+	cmd.WaitDelay = time.Second
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Printf("rule %s: hook: %s: %s\n", r.Name, err, out)
+	}
+}
+
+// TestRules prints the rules that match a stored item and what they would
+// do
+func (c Commands) TestRules(ID int) error {
+	rules, err := compileRules(c.config.Rules)
+	if err != nil {
+		return fmt.Errorf("commands TestRules: %w", err)
+	}
+
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("commands TestRules: %w", err)
+	}
+
+	ms := matchingRules(rules, item)
+	if len(ms) == 0 {
+		fmt.Printf("no rules match %d. %s\n", item.ID, item.Title)
+		return nil
+	}
+
+	fmt.Printf("%d. %s\n", item.ID, item.Title)
+	for _, r := range ms {
+		fmt.Printf("  - %s: %s\n", r.Name, describeActions(r.Actions))
+	}
+
+	return nil
+}
+
+func describeActions(a config.RuleActions) string {
+	var actions []string
+	if a.Delete {
+		actions = append(actions, "delete")
+	}
+	if a.Read {
+		actions = append(actions, "mark read")
+	}
+	if a.Favourite {
+		actions = append(actions, "favourite")
+	}
+	for _, t := range a.Tags {
+		actions = append(actions, "tag "+t)
+	}
+	if a.Hook != "" {
+		actions = append(actions, "run "+a.Hook)
+	}
+
+	if len(actions) == 0 {
+		return "no actions"
+	}
+	return strings.Join(actions, ", ")
+}
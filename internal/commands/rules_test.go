@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestMatchingRules(t *testing.T) {
+	rules, err := compileRules([]config.Rule{
+		{Name: "sponsored", Match: config.RuleMatch{Title: `^\[sponsored\]`}, Actions: config.RuleActions{Delete: true}},
+		{Name: "go releases", Match: config.RuleMatch{Feeds: []string{"go blog"}, Categories: []string{"Releases"}}, Actions: config.RuleActions{Tags: []string{"releases"}}},
+		{Name: "alice", Match: config.RuleMatch{Author: "alice", Content: "generics"}, Actions: config.RuleActions{Favourite: true}},
+	})
+	test.HandleError(t, err)
+
+	names := func(item store.Item) string {
+		var ns []string
+		for _, r := range matchingRules(rules, item) {
+			ns = append(ns, r.Name)
+		}
+		return strings.Join(ns, ", ")
+	}
+
+	test.Equal(t, "sponsored", names(store.Item{Title: "[Sponsored] Buy this"}), "title regex should be case insensitive")
+	test.Equal(t, "go releases", names(store.Item{FeedName: "Go Blog", Title: "Go 1.22", Categories: []string{"releases"}}), "feed and category should match")
+	test.Equal(t, "", names(store.Item{FeedName: "Go Blog", Title: "Go 1.22"}), "category is required")
+	test.Equal(t, "go releases", names(store.Item{FeedURL: "go blog", Categories: []string{"releases"}}), "feed should match the url")
+	test.Equal(t, "alice", names(store.Item{Author: "Alice Smith", Content: "<p>Generics</p>"}), "author and content should match")
+	test.Equal(t, "", names(store.Item{Author: "Alice Smith"}), "content is required")
+
+	test.Equal(t, true, deletes(matchingRules(rules, store.Item{Title: "[sponsored] x"})), "sponsored should be deleted")
+	test.Equal(t, false, deletes(matchingRules(rules, store.Item{Author: "alice", Content: "generics"})), "alice should be kept")
+}
+
+func TestCompileRulesInvalid(t *testing.T) {
+	_, err := compileRules([]config.Rule{{Name: "bad", Match: config.RuleMatch{Title: "("}}})
+	if err == nil || !strings.Contains(err.Error(), "rule bad: title") {
+		t.Fatalf("expected an error naming the rule, got %v", err)
+	}
+}
+
+func TestRunHookTimeout(t *testing.T) {
+	defer func(d time.Duration) { hookTimeout = d }(hookTimeout)
+	hookTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	runHook(rule{Rule: config.Rule{Name: "slow", Actions: config.RuleActions{Hook: "sleep 10"}}}, store.Item{})
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("hung hook wasn't killed")
+	}
+}
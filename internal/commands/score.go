@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// scoreItem adds up the scores of the rules matching an item
+func scoreItem(item store.Item, rules []config.ScoreRule) int {
+	score := 0
+	for _, r := range rules {
+		if matchesScoreRule(item, r) {
+			score += r.Score
+		}
+	}
+
+	return score
+}
+
+func matchesScoreRule(item store.Item, r config.ScoreRule) bool {
+	if !config.MatchFeed(r.Feeds, item.FeedName, item.FeedURL) {
+		return false
+	}
+
+	if len(r.Keywords) > 0 {
+		title, content := strings.ToLower(item.Title), strings.ToLower(item.Content)
+		if !slices.ContainsFunc(r.Keywords, func(k string) bool {
+			k = strings.ToLower(k)
+			return strings.Contains(title, k) || strings.Contains(content, k)
+		}) {
+			return false
+		}
+	}
+
+	if !config.MatchAny(r.Authors, []string{item.Author}) {
+		return false
+	}
+
+	if !config.MatchAny(r.Tags, slices.Concat(item.Categories, item.Tags)) {
+		return false
+	}
+
+	return true
+}
+
+// rescore stores the score of every item, so changes to the scoring rules
+// apply to items already fetched
+func (c Commands) rescore() error {
+	items, err := c.store.GetAllItems(c.config.Ordering)
+	if err != nil {
+		return fmt.Errorf("rescore: %w", err)
+	}
+
+	scores := make(map[int]int, len(items))
+	for _, item := range items {
+		scores[item.ID] = scoreItem(item, c.config.Scoring)
+	}
+
+	err = c.store.SetScores(scores)
+	if err != nil {
+		return fmt.Errorf("rescore: %w", err)
+	}
+
+	return nil
+}
+
+// rescoreItem stores the score of one item, after its tags change
+func (c Commands) rescoreItem(ID int) error {
+	item, err := c.store.GetItemByID(ID)
+	if err != nil {
+		return fmt.Errorf("rescoreItem: %w", err)
+	}
+
+	err = c.store.SetScores(map[int]int{ID: scoreItem(item, c.config.Scoring)})
+	if err != nil {
+		return fmt.Errorf("rescoreItem: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestScoreItem(t *testing.T) {
+	rules := []config.ScoreRule{
+		{Score: 10, Feeds: []string{"go blog"}},
+		{Score: 5, Keywords: []string{"Generics", "iterators"}},
+		{Score: 3, Authors: []string{"alice"}, Tags: []string{"golang"}},
+		{Score: -20, Keywords: []string{"sponsored"}},
+	}
+
+	cases := []struct {
+		name string
+		item store.Item
+		want int
+	}{
+		{"nothing", store.Item{Title: "Weekly links"}, 0},
+		{"feed", store.Item{FeedName: "Go Blog", Title: "Go 1.22"}, 10},
+		{"feed url", store.Item{FeedURL: "go blog"}, 10},
+		{"keyword in content", store.Item{Title: "Go 1.22", Content: "<p>range over ITERATORS</p>"}, 5},
+		{"rules add up", store.Item{FeedName: "go blog", Title: "More generics"}, 15},
+		{"author and tag", store.Item{Author: "Alice", Categories: []string{"Golang"}}, 3},
+		{"author without tag", store.Item{Author: "Alice"}, 0},
+		{"user tag", store.Item{Author: "alice", Tags: []string{"golang"}}, 3},
+		{"negative", store.Item{FeedName: "go blog", Title: "Sponsored: buy this"}, -10},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			test.Equal(t, c.want, scoreItem(c.item, rules), "wrong score")
+		})
+	}
+}
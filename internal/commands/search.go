@@ -0,0 +1,345 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// styles for matches in the article, put over glamour's own
+const (
+	matchStyle        = "\x1b[30;43m"
+	currentMatchStyle = "\x1b[30;46m"
+	resetStyle        = "\x1b[0m"
+)
+
+// searchMatch is where a match is in the article, in runes of the line
+// without its styling
+type searchMatch struct {
+	line  int
+	start int
+	end   int
+}
+
+// articleSearch is a search in the open article, typed after / or ?. A
+// search started with ? goes up the article, so n and N are swapped.
+type articleSearch struct {
+	input    textinput.Model
+	typing   bool
+	backward bool
+	// origin is where the article was scrolled to when the search started,
+	// which the first match is looked for from
+	origin  int
+	matches []searchMatch
+	current int
+}
+
+// escapeSeq returns the terminal escape sequence at the start of s
+func escapeSeq(s string) string {
+	if len(s) < 2 {
+		return s
+	}
+
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return s[:i+1]
+			}
+		}
+	// OSC, and DCS and APC as sixel and kitty images use
+	case ']', 'P', '_':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return s[:i+1]
+			}
+			if s[i] == '\x1b' && i+1 < len(s) && s[i+1] == '\\' {
+				return s[:i+2]
+			}
+		}
+	default:
+		return s[:2]
+	}
+
+	return s
+}
+
+// plainLine is a line of the article without its styling
+func plainLine(line string) string {
+	var b strings.Builder
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			i += len(escapeSeq(line[i:]))
+			continue
+		}
+
+		b.WriteByte(line[i])
+		i++
+	}
+
+	return b.String()
+}
+
+// findMatches finds query in every line of the rendered article. Like vim's
+// smartcase, it only matches case when the query has capitals.
+func findMatches(content string, query string) []searchMatch {
+	if query == "" {
+		return nil
+	}
+
+	fold := strings.ToLower(query) == query
+	normalise := func(s string) []rune {
+		r := []rune(s)
+		if fold {
+			for i := range r {
+				r[i] = unicode.ToLower(r[i])
+			}
+		}
+		return r
+	}
+
+	q := normalise(query)
+
+	var matches []searchMatch
+	for n, line := range strings.Split(content, "\n") {
+		text := normalise(plainLine(line))
+		for i := 0; i+len(q) <= len(text); {
+			if string(text[i:i+len(q)]) == string(q) {
+				matches = append(matches, searchMatch{line: n, start: i, end: i + len(q)})
+				i += len(q)
+				continue
+			}
+			i++
+		}
+	}
+
+	return matches
+}
+
+// highlightLine styles the spans of a line between start and end runes of
+// its text. Glamour resets its styling often, so the highlight is put back
+// after each of its sequences, and the styling before a match after it.
+func highlightLine(line string, matches []searchMatch, styles []string) string {
+	var (
+		b      strings.Builder
+		active strings.Builder
+		pos    int
+		m      int
+		in     bool
+	)
+
+	for i := 0; i < len(line); {
+		if line[i] == '\x1b' {
+			seq := escapeSeq(line[i:])
+			b.WriteString(seq)
+			if strings.HasSuffix(seq, "m") && strings.HasPrefix(seq, "\x1b[") {
+				if seq == resetStyle || seq == "\x1b[m" || strings.HasPrefix(seq, "\x1b[0;") {
+					active.Reset()
+				}
+				if seq != resetStyle && seq != "\x1b[m" {
+					active.WriteString(seq)
+				}
+				if in {
+					b.WriteString(styles[m])
+				}
+			}
+			i += len(seq)
+			continue
+		}
+
+		if !in && m < len(matches) && pos == matches[m].start {
+			b.WriteString(styles[m])
+			in = true
+		}
+
+		_, size := utf8.DecodeRuneInString(line[i:])
+		b.WriteString(line[i : i+size])
+		i += size
+		pos++
+
+		if in && pos == matches[m].end {
+			b.WriteString(resetStyle)
+			b.WriteString(active.String())
+			in = false
+			m++
+		}
+	}
+
+	if in {
+		b.WriteString(resetStyle)
+	}
+
+	return b.String()
+}
+
+// highlightMatches styles every match in the article, the current one apart
+func highlightMatches(content string, matches []searchMatch, current int) string {
+	if len(matches) == 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(matches); {
+		n := matches[i].line
+
+		var (
+			ms     []searchMatch
+			styles []string
+		)
+		for ; i < len(matches) && matches[i].line == n; i++ {
+			ms = append(ms, matches[i])
+			if i == current {
+				styles = append(styles, currentMatchStyle)
+			} else {
+				styles = append(styles, matchStyle)
+			}
+		}
+
+		lines[n] = highlightLine(lines[n], ms, styles)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// setArticle shows rendered content in the article view, with any search
+// matches in it highlighted
+func (m *model) setArticle(content string) {
+	m.article = content
+
+	if m.search != nil {
+		m.search.matches = findMatches(content, m.search.input.Value())
+		m.search.current = min(m.search.current, max(len(m.search.matches)-1, 0))
+		content = highlightMatches(content, m.search.matches, m.search.current)
+	}
+
+	m.viewport.SetContent(content)
+}
+
+func (m *model) startSearch(backward bool) tea.Cmd {
+	ti := textinput.New()
+	ti.Prompt = "/"
+	if backward {
+		ti.Prompt = "?"
+	}
+	ti.Focus()
+
+	m.search = &articleSearch{
+		input:    ti,
+		typing:   true,
+		backward: backward,
+		origin:   m.viewport.YOffset,
+	}
+
+	return textinput.Blink
+}
+
+func (m *model) clearSearch() {
+	m.search = nil
+	m.viewport.SetContent(m.article)
+}
+
+// firstMatch is the first match below where the search started, or above it
+// searching backwards, wrapping around the article
+func (s *articleSearch) firstMatch() int {
+	if s.backward {
+		for i := len(s.matches) - 1; i >= 0; i-- {
+			if s.matches[i].line <= s.origin {
+				return i
+			}
+		}
+		return len(s.matches) - 1
+	}
+
+	for i, match := range s.matches {
+		if match.line >= s.origin {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// showMatch highlights the current match and scrolls it into the middle of
+// the article view
+func (m *model) showMatch() {
+	s := m.search
+	m.viewport.SetContent(highlightMatches(m.article, s.matches, s.current))
+
+	if len(s.matches) > 0 {
+		m.viewport.SetYOffset(s.matches[s.current].line - m.viewport.Height/2)
+	}
+}
+
+// nextMatch moves to the next match, or the previous, in the direction the
+// search was made
+func (m *model) nextMatch(forward bool) {
+	s := m.search
+	if s == nil || len(s.matches) == 0 {
+		return
+	}
+
+	if forward == s.backward {
+		s.current = (s.current - 1 + len(s.matches)) % len(s.matches)
+	} else {
+		s.current = (s.current + 1) % len(s.matches)
+	}
+
+	m.showMatch()
+}
+
+// updateSearch takes the keys while a search is typed, finding matches as it
+// goes
+func updateSearch(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	s := m.search
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, SearchKeyMap.Cancel):
+			m.clearSearch()
+			m.viewport.SetYOffset(s.origin)
+			return m, nil
+
+		case key.Matches(keyMsg, SearchKeyMap.Confirm):
+			s.typing = false
+			if s.input.Value() == "" {
+				m.clearSearch()
+			} else if len(s.matches) == 0 {
+				m.status = "Not found: " + s.input.Value()
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	s.input, cmd = s.input.Update(msg)
+
+	s.matches = findMatches(m.article, s.input.Value())
+	s.current = s.firstMatch()
+	if len(s.matches) == 0 {
+		m.viewport.SetContent(m.article)
+		m.viewport.SetYOffset(s.origin)
+	} else {
+		m.showMatch()
+	}
+
+	return m, cmd
+}
+
+// count is where the current match is among them all
+func (s *articleSearch) count() string {
+	if len(s.matches) == 0 {
+		return "[0/0]"
+	}
+
+	return fmt.Sprintf("[%d/%d]", s.current+1, len(s.matches))
+}
+
+// status is the search and its count, for the footer
+func (s *articleSearch) status() string {
+	return s.input.Prompt + s.input.Value() + " " + s.count()
+}
@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFindMatches(t *testing.T) {
+	content := "\x1b[1mGo\x1b[0m is fun, go \x1b[38;5;252mgo\x1b[0m\nnothing here\nGOPHER"
+
+	matches := findMatches(content, "go")
+	test.Equal(t, 4, len(matches), "lowercase matches any case")
+	test.Equal(t, searchMatch{line: 0, start: 14, end: 16}, matches[2], "styling is skipped")
+	test.Equal(t, searchMatch{line: 2, start: 0, end: 2}, matches[3], "later lines")
+
+	test.Equal(t, 1, len(findMatches(content, "Go")), "capitals match case")
+	test.Equal(t, 0, len(findMatches(content, "")), "empty query")
+}
+
+func TestHighlightLine(t *testing.T) {
+	line := "\x1b[1mhello\x1b[0m world"
+	matches := []searchMatch{{start: 3, end: 8}}
+
+	got := highlightLine(line, matches, []string{matchStyle})
+	want := "\x1b[1mhel" + matchStyle + "lo\x1b[0m" + matchStyle + " wo" + resetStyle + "rld"
+	test.Equal(t, want, got, "highlight survives resets")
+	test.Equal(t, plainLine(line), plainLine(got), "text unchanged")
+
+	line = "\x1b[32mgreen text\x1b[0m"
+	got = highlightLine(line, []searchMatch{{start: 0, end: 5}}, []string{currentMatchStyle})
+	want = "\x1b[32m" + currentMatchStyle + "green" + resetStyle + "\x1b[32m text\x1b[0m"
+	test.Equal(t, want, got, "styling before the match is put back")
+}
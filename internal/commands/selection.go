@@ -0,0 +1,171 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selectedIDs are the selected items, in list order
+func (m *model) selectedIDs() []int {
+	var ids []int
+	for _, item := range m.list.Items() {
+		if id := item.(TUIItem).ID; m.selected[id] {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// toggleSelected selects or deselects the item under the cursor, which
+// becomes the start of the next range
+func (m *model) toggleSelected() {
+	item, ok := m.list.SelectedItem().(TUIItem)
+	if !ok {
+		return
+	}
+
+	if m.selected[item.ID] {
+		delete(m.selected, item.ID)
+	} else {
+		m.selected[item.ID] = true
+	}
+	m.selectAnchor = item.ID
+
+	m.list.CursorDown()
+}
+
+// selectRange selects the shown items from the last one toggled to the cursor
+func (m *model) selectRange() {
+	items := m.list.VisibleItems()
+	cursor := m.list.Index()
+	if cursor >= len(items) {
+		return
+	}
+
+	anchor := slices.IndexFunc(items, func(i list.Item) bool {
+		return i.(TUIItem).ID == m.selectAnchor
+	})
+	if anchor < 0 {
+		anchor = cursor
+	}
+
+	for i := min(anchor, cursor); i <= max(anchor, cursor); i++ {
+		m.selected[items[i].(TUIItem).ID] = true
+	}
+	m.selectAnchor = items[cursor].(TUIItem).ID
+}
+
+// selectAll selects every shown item, so all that match a filter, or
+// clears the selection if they already are
+func (m *model) selectAll() {
+	items := m.list.VisibleItems()
+
+	all := true
+	for _, item := range items {
+		if !m.selected[item.(TUIItem).ID] {
+			all = false
+			break
+		}
+	}
+
+	for _, item := range items {
+		if all {
+			delete(m.selected, item.(TUIItem).ID)
+		} else {
+			m.selected[item.(TUIItem).ID] = true
+		}
+	}
+}
+
+// pruneSelected drops items no longer in the list from the selection
+func (m *model) pruneSelected(items []list.Item) {
+	shown := make(map[int]bool, len(items))
+	for _, item := range items {
+		shown[item.(TUIItem).ID] = true
+	}
+
+	for id := range m.selected {
+		if !shown[id] {
+			delete(m.selected, id)
+		}
+	}
+}
+
+// bulkRead marks the selection read, or unread if it all is already
+func (m *model) bulkRead() tea.Cmd {
+	ids := m.selectedIDs()
+
+	read := false
+	for _, item := range m.list.Items() {
+		if it := item.(TUIItem); m.selected[it.ID] && !it.Read {
+			read = true
+			break
+		}
+	}
+
+	err := m.record("read", ids, func() error {
+		return m.commands.setRead(ids, read)
+	})
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	state := "unread"
+	if read {
+		state = "read"
+	}
+
+	return m.finishBulk(fmt.Sprintf("Marked %d %s.", len(ids), state))
+}
+
+// bulkFavourite favourites the selection, or unfavourites it if it all is
+// already
+func (m *model) bulkFavourite() tea.Cmd {
+	ids := m.selectedIDs()
+
+	favourite := false
+	for _, item := range m.list.Items() {
+		if it := item.(TUIItem); m.selected[it.ID] && !it.Favourite {
+			favourite = true
+			break
+		}
+	}
+
+	err := m.record("favourite", ids, func() error {
+		return m.commands.store.SetFavourite(ids, favourite)
+	})
+	if err != nil {
+		return m.list.NewStatusMessage(err.Error())
+	}
+
+	state := "Unfavourited"
+	if favourite {
+		state = "Favourited"
+	}
+
+	return m.finishBulk(fmt.Sprintf("%s %d.", state, len(ids)))
+}
+
+// bulkOpen opens the link of every selected item
+func (m *model) bulkOpen() tea.Cmd {
+	var cmds []tea.Cmd
+	for _, item := range m.list.Items() {
+		if it := item.(TUIItem); m.selected[it.ID] {
+			cmds = append(cmds, m.OpenLink(it.URL))
+		}
+	}
+
+	clear(m.selected)
+
+	return tea.Sequence(append(cmds, m.list.NewStatusMessage(fmt.Sprintf("Opening %d...", len(cmds))))...)
+}
+
+func (m *model) finishBulk(status string) tea.Cmd {
+	clear(m.selected)
+
+	return tea.Batch(m.UpdateList(), m.list.NewStatusMessage(status))
+}
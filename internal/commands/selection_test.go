@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func selectionModel() model {
+	var items []list.Item
+	for id := 1; id <= 5; id++ {
+		items = append(items, TUIItem{ID: id})
+	}
+
+	selected := make(map[int]bool)
+	return model{
+		list:     list.New(items, itemDelegate{selected: selected}, 80, 20),
+		selected: selected,
+	}
+}
+
+func TestSelection(t *testing.T) {
+	m := selectionModel()
+
+	// toggling moves on to the next item
+	m.toggleSelected()
+	test.Equal(t, 1, m.list.Index(), "cursor moved")
+	test.Equal(t, true, m.selected[1], "first selected")
+
+	m.list.Select(3)
+	m.selectRange()
+	test.Equal(t, 4, len(m.selected), "range from the last toggled")
+	test.Equal(t, false, m.selected[5], "past the range")
+
+	m.selectAll()
+	test.Equal(t, 5, len(m.selected), "all selected")
+	m.selectAll()
+	test.Equal(t, 0, len(m.selected), "all cleared")
+}
+
+func TestPruneSelected(t *testing.T) {
+	m := selectionModel()
+	m.selected[2] = true
+	m.selected[9] = true
+
+	m.pruneSelected(m.list.Items())
+	test.Equal(t, 1, len(m.selected), "gone items dropped")
+	test.Equal(t, true, m.selected[2], "shown item kept")
+}
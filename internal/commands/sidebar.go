@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+const sidebarWidth = 32
+
+var (
+	sidebarStyle = lipgloss.NewStyle().
+			Width(sidebarWidth).
+			PaddingLeft(2).
+			BorderStyle(lipgloss.NormalBorder()).
+			BorderRight(true).
+			BorderForeground(lipgloss.Color("240"))
+	sidebarActiveStyle = lipgloss.NewStyle().Bold(true)
+	sidebarCountStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// sidebarNode is a group of feeds, or a feed. The root is every feed.
+type sidebarNode struct {
+	name     string
+	group    string // the full path of a group
+	feedURL  string // only set for feeds
+	unread   int
+	children []*sidebarNode
+}
+
+func (n *sidebarNode) isFeed() bool {
+	return n.feedURL != ""
+}
+
+// buildSidebarTree nests feeds under their groups, groups first, and adds up
+// the unread counts of each group
+func buildSidebarTree(feeds []config.Feed, unread map[string]int) *sidebarNode {
+	root := &sidebarNode{name: "All"}
+	groups := map[string]*sidebarNode{"": root}
+
+	var group func(path string) *sidebarNode
+	group = func(path string) *sidebarNode {
+		if n, ok := groups[path]; ok {
+			return n
+		}
+
+		parent, name := "", path
+		if i := strings.LastIndex(path, "/"); i >= 0 {
+			parent, name = path[:i], path[i+1:]
+		}
+
+		n := &sidebarNode{name: name, group: path}
+		p := group(parent)
+		p.children = append(p.children, n)
+		groups[path] = n
+		return n
+	}
+
+	for _, f := range feeds {
+		name := f.Name
+		if name == "" {
+			name = f.URL
+		}
+
+		g := group(f.GroupPath())
+		g.children = append(g.children, &sidebarNode{name: name, feedURL: f.URL, unread: unread[f.URL]})
+	}
+
+	var sum func(n *sidebarNode) int
+	sum = func(n *sidebarNode) int {
+		if n.isFeed() {
+			return n.unread
+		}
+
+		n.unread = 0
+		for _, c := range n.children {
+			n.unread += sum(c)
+		}
+
+		slices.SortStableFunc(n.children, func(a, b *sidebarNode) int {
+			switch {
+			case !a.isFeed() && b.isFeed():
+				return -1
+			case a.isFeed() && !b.isFeed():
+				return 1
+			}
+			return 0
+		})
+
+		return n.unread
+	}
+	sum(root)
+
+	return root
+}
+
+// sidebar is a collapsible tree of groups and feeds. Selecting a node shows
+// only its items in the list.
+type sidebar struct {
+	root      *sidebarNode
+	collapsed map[string]bool
+	cursor    int
+	focused   bool
+}
+
+type sidebarRow struct {
+	node  *sidebarNode
+	depth int
+}
+
+// rows are the nodes that aren't hidden by a collapsed group, in order
+func (s *sidebar) rows() []sidebarRow {
+	var rows []sidebarRow
+
+	var walk func(n *sidebarNode, depth int)
+	walk = func(n *sidebarNode, depth int) {
+		rows = append(rows, sidebarRow{node: n, depth: depth})
+		if n != s.root && s.collapsed[n.group] {
+			return
+		}
+		for _, c := range n.children {
+			walk(c, depth+1)
+		}
+	}
+	walk(s.root, 0)
+
+	return rows
+}
+
+func (s *sidebar) selected() *sidebarNode {
+	rows := s.rows()
+	return rows[min(s.cursor, len(rows)-1)].node
+}
+
+// setCollapsed folds or unfolds the group under the cursor
+func (s *sidebar) setCollapsed(collapsed bool) {
+	n := s.selected()
+	if n == s.root || n.isFeed() {
+		return
+	}
+
+	s.collapsed[n.group] = collapsed
+}
+
+func (m *model) refreshSidebar() {
+	if m.sidebar == nil {
+		return
+	}
+
+	unread, err := m.commands.store.CountUnreadByFeed()
+	if err != nil {
+		m.list.NewStatusMessage(err.Error())
+	}
+
+	m.sidebar.root = buildSidebarTree(m.commands.config.GetFeeds(), unread)
+	m.sidebar.cursor = min(m.sidebar.cursor, len(m.sidebar.rows())-1)
+}
+
+func (m *model) toggleSidebar() tea.Cmd {
+	if m.sidebar != nil {
+		m.sidebar = nil
+		m.resize()
+
+		// don't leave the list narrowed by a pane that is gone
+		if m.commands.config.ActiveGroup != "" || m.commands.config.ActiveFeed != "" {
+			m.commands.config.ActiveGroup = ""
+			m.commands.config.ActiveFeed = ""
+			m.list.NewStatusMessage("")
+			return m.UpdateList()
+		}
+		return nil
+	}
+
+	m.sidebar = &sidebar{collapsed: make(map[string]bool), focused: true}
+	m.refreshSidebar()
+	m.resize()
+
+	return nil
+}
+
+func updateSidebar(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return updateList(msg, m)
+	}
+
+	s := m.sidebar
+
+	switch {
+	case key.Matches(keyMsg, SidebarKeyMap.Quit):
+		return m, tea.Quit
+
+	case key.Matches(keyMsg, SidebarKeyMap.Close):
+		return m, m.toggleSidebar()
+
+	case key.Matches(keyMsg, SidebarKeyMap.Focus):
+		s.focused = false
+
+	case key.Matches(keyMsg, SidebarKeyMap.Up):
+		s.cursor = max(s.cursor-1, 0)
+
+	case key.Matches(keyMsg, SidebarKeyMap.Down):
+		s.cursor = min(s.cursor+1, len(s.rows())-1)
+
+	case key.Matches(keyMsg, SidebarKeyMap.Expand):
+		s.setCollapsed(false)
+
+	case key.Matches(keyMsg, SidebarKeyMap.Collapse):
+		s.setCollapsed(true)
+
+	case key.Matches(keyMsg, SidebarKeyMap.Toggle):
+		n := s.selected()
+		s.setCollapsed(!s.collapsed[n.group])
+
+	case key.Matches(keyMsg, SidebarKeyMap.MarkRead):
+		return m, m.markNodeRead(s.selected())
+
+	case key.Matches(keyMsg, SidebarKeyMap.Select):
+		n := s.selected()
+
+		cfg := m.commands.config
+		cfg.ActiveGroup, cfg.ActiveFeed = "", ""
+		switch {
+		case n == s.root:
+			m.list.NewStatusMessage("")
+		case n.isFeed():
+			cfg.ActiveFeed = n.feedURL
+			m.list.NewStatusMessage("feed: " + n.name)
+		default:
+			cfg.ActiveGroup = n.group
+			m.list.NewStatusMessage("group: " + n.group)
+		}
+
+		m.list.Select(0)
+		return m, m.UpdateList()
+	}
+
+	return m, nil
+}
+
+func sidebarView(m model) string {
+	s := m.sidebar
+	rows := s.rows()
+	cfg := m.commands.config
+
+	// the title and a blank line sit above the rows, as in the list
+	height := max(m.height-3, 1)
+	start := max(0, s.cursor-height+1)
+
+	var b strings.Builder
+	b.WriteString(titleStyle.
+		Background(lipgloss.Color(m.cfg.Theme.TitleColor)).
+		Foreground(lipgloss.Color(m.cfg.Theme.TitleColorFg)).
+		Width(len("feeds") + 2).
+		Render("feeds"))
+	b.WriteString("\n\n")
+
+	for i := start; i < len(rows) && i < start+height; i++ {
+		n, depth := rows[i].node, rows[i].depth
+
+		marker := "  "
+		if !n.isFeed() && n != s.root {
+			marker = "▾ "
+			if s.collapsed[n.group] {
+				marker = "▸ "
+			}
+		}
+
+		count := ""
+		if n.unread > 0 {
+			count = fmt.Sprintf(" %d", n.unread)
+		}
+
+		indent := strings.Repeat("  ", max(depth-1, 0))
+		name := truncate(n.name, max(sidebarWidth-4-len(indent)-lipgloss.Width(marker)-len(count), 1))
+		line := indent + marker + name
+
+		active := (n == s.root && cfg.ActiveGroup == "" && cfg.ActiveFeed == "") ||
+			(n.isFeed() && n.feedURL == cfg.ActiveFeed) ||
+			(!n.isFeed() && n != s.root && n.group == cfg.ActiveGroup)
+		if active {
+			line = sidebarActiveStyle.Render(line)
+		}
+
+		if i == s.cursor {
+			color := lipgloss.Color("240")
+			if s.focused {
+				color = lipgloss.Color(m.cfg.Theme.SelectedItemColor)
+			}
+			line = lipgloss.NewStyle().Foreground(color).Render(line)
+		}
+
+		b.WriteString(line + sidebarCountStyle.Render(count) + "\n")
+	}
+
+	return sidebarStyle.Height(m.height).Render(b.String())
+}
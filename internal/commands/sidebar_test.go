@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+var sidebarFeeds = []config.Feed{
+	{URL: "https://a.example/rss", Name: "A"},
+	{URL: "https://go.example/rss", Name: "Go", Group: "tech/lang"},
+	{URL: "https://hn.example/rss", Name: "HN", Group: "tech"},
+	{URL: "https://rust.example/rss", Group: "/tech//lang/"},
+}
+
+func TestBuildSidebarTree(t *testing.T) {
+	unread := map[string]int{
+		"https://a.example/rss":    1,
+		"https://go.example/rss":   2,
+		"https://hn.example/rss":   3,
+		"https://rust.example/rss": 4,
+	}
+	root := buildSidebarTree(sidebarFeeds, unread)
+
+	test.Equal(t, 10, root.unread, "root unread")
+	test.Equal(t, 2, len(root.children), "root children")
+
+	// groups come before feeds
+	tech := root.children[0]
+	test.Equal(t, "tech", tech.group, "first child")
+	test.Equal(t, 9, tech.unread, "tech unread")
+	test.Equal(t, "https://a.example/rss", root.children[1].feedURL, "second child")
+
+	lang := tech.children[0]
+	test.Equal(t, "tech/lang", lang.group, "nested group")
+	test.Equal(t, "lang", lang.name, "nested group name")
+	test.Equal(t, 6, lang.unread, "lang unread")
+	test.Equal(t, 2, len(lang.children), "lang children")
+	test.Equal(t, "https://rust.example/rss", lang.children[1].name, "unnamed feed uses its url")
+}
+
+func TestSidebarRows(t *testing.T) {
+	s := sidebar{root: buildSidebarTree(sidebarFeeds, nil), collapsed: make(map[string]bool)}
+	test.Equal(t, 7, len(s.rows()), "expanded rows")
+
+	s.cursor = 1
+	s.setCollapsed(true)
+	rows := s.rows()
+	test.Equal(t, 3, len(rows), "collapsed rows")
+	test.Equal(t, "tech", rows[1].node.group, "collapsed group still shown")
+	test.Equal(t, 1, rows[1].depth, "group depth")
+
+	// the root can't be collapsed
+	s.cursor = 0
+	s.setCollapsed(false)
+	s.setCollapsed(true)
+	test.Equal(t, 3, len(s.rows()), "root collapse")
+}
@@ -0,0 +1,255 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// maximum number of tags shown in the picker at once
+const tagPickerHeight = 10
+
+var (
+	pickerStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("62")).
+			Padding(0, 1).
+			Width(40)
+	pickerTitleStyle = lipgloss.NewStyle().Bold(true)
+	pickerHelpStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+// tagPicker is a popup for adding and removing user tags on items. Typing
+// narrows the known tags, or names a new one.
+type tagPicker struct {
+	itemIDs []int
+	title   string
+	input   textinput.Model
+	all     []string
+	// applied are the tags every item has
+	applied []string
+	cursor  int
+}
+
+func (m model) newTagPicker(itemIDs ...int) (*tagPicker, error) {
+	var title string
+	var applied []string
+	for i, id := range itemIDs {
+		item, err := m.commands.store.GetItemByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("newTagPicker: %w", err)
+		}
+
+		if i == 0 {
+			title = item.Title
+			applied = item.Tags
+			continue
+		}
+
+		applied = slices.DeleteFunc(applied, func(t string) bool {
+			return !slices.Contains(item.Tags, t)
+		})
+	}
+	if len(itemIDs) > 1 {
+		title = fmt.Sprintf("%d items", len(itemIDs))
+	}
+
+	all, err := m.commands.store.ListTags()
+	if err != nil {
+		return nil, fmt.Errorf("newTagPicker: %w", err)
+	}
+
+	ti := textinput.New()
+	ti.Placeholder = "tag"
+	ti.Prompt = "# "
+	ti.Focus()
+
+	return &tagPicker{
+		itemIDs: itemIDs,
+		title:   title,
+		input:   ti,
+		all:     all,
+		applied: applied,
+	}, nil
+}
+
+// options returns the tags matching the input, with the input itself first
+// if it isn't an existing tag.
+func (p *tagPicker) options() []string {
+	term := strings.ToLower(strings.TrimSpace(p.input.Value()))
+
+	var opts []string
+	exists := false
+	for _, t := range p.all {
+		lt := strings.ToLower(t)
+		if lt == term {
+			exists = true
+		}
+		if strings.Contains(lt, term) {
+			opts = append(opts, t)
+		}
+	}
+
+	if term != "" && !exists {
+		opts = append([]string{strings.TrimSpace(p.input.Value())}, opts...)
+	}
+
+	return opts
+}
+
+func (p *tagPicker) isApplied(tag string) bool {
+	return slices.ContainsFunc(p.applied, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
+
+func (m model) openTagPicker(itemIDs ...int) (model, tea.Cmd) {
+	p, err := m.newTagPicker(itemIDs...)
+	if err != nil {
+		return m, m.list.NewStatusMessage(err.Error())
+	}
+
+	m.tagPicker = p
+
+	return m, textinput.Blink
+}
+
+func (m model) closeTagPicker() (model, tea.Cmd) {
+	if len(m.tagPicker.itemIDs) > 1 {
+		clear(m.selected)
+	}
+	m.tagPicker = nil
+
+	if m.selectedArticle != nil {
+		content, err := m.commands.renderArticle(*m.selectedArticle)
+		if err == nil {
+			m.setArticle(content)
+		}
+	}
+
+	return m, m.UpdateList()
+}
+
+func updateTagPicker(keyMsg tea.KeyMsg, m model) (tea.Model, tea.Cmd) {
+	p := m.tagPicker
+
+	switch {
+	case key.Matches(keyMsg, TagPickerKeyMap.Close):
+		return m.closeTagPicker()
+
+	case key.Matches(keyMsg, TagPickerKeyMap.Up):
+		if p.cursor > 0 {
+			p.cursor--
+		}
+		return m, nil
+
+	case key.Matches(keyMsg, TagPickerKeyMap.Down):
+		if p.cursor < len(p.options())-1 {
+			p.cursor++
+		}
+		return m, nil
+
+	case key.Matches(keyMsg, TagPickerKeyMap.Toggle):
+		opts := p.options()
+		if len(opts) == 0 {
+			return m, nil
+		}
+		tag := opts[min(p.cursor, len(opts)-1)]
+
+		// a tag only some of the items have is added to the rest
+		applied := p.isApplied(tag)
+		err := m.record("tag "+tag, p.itemIDs, func() error {
+			if err := m.commands.store.SetTag(p.itemIDs, tag, !applied); err != nil {
+				return err
+			}
+
+			// tags can change the score
+			for _, id := range p.itemIDs {
+				if err := m.commands.rescoreItem(id); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			return m, m.list.NewStatusMessage(err.Error())
+		}
+
+		if applied {
+			p.applied = slices.DeleteFunc(p.applied, func(t string) bool {
+				return strings.EqualFold(t, tag)
+			})
+		} else {
+			p.applied = append(p.applied, tag)
+			if !slices.Contains(p.all, tag) {
+				p.all = append(p.all, tag)
+				slices.Sort(p.all)
+			}
+		}
+
+		p.input.SetValue("")
+		p.cursor = 0
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(keyMsg)
+	p.cursor = 0
+
+	return m, cmd
+}
+
+func tagPickerView(m model) string {
+	p := m.tagPicker
+
+	var b strings.Builder
+	b.WriteString(pickerTitleStyle.Render(truncate("Tags: "+p.title, 38)))
+	b.WriteString("\n\n")
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+
+	opts := p.options()
+	if len(opts) == 0 {
+		b.WriteString(pickerHelpStyle.Render("no tags yet, type to create one"))
+		b.WriteString("\n")
+	}
+
+	// keep the cursor in view
+	start := max(0, p.cursor-tagPickerHeight+1)
+	for i := start; i < len(opts) && i < start+tagPickerHeight; i++ {
+		check := "[ ]"
+		if p.isApplied(opts[i]) {
+			check = "[x]"
+		}
+
+		line := fmt.Sprintf("  %s %s", check, opts[i])
+		if i == p.cursor {
+			line = lipgloss.NewStyle().
+				Foreground(lipgloss.Color(m.cfg.Theme.SelectedItemColor)).
+				Render(fmt.Sprintf("> %s %s", check, opts[i]))
+		}
+
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(pickerHelpStyle.Render("enter toggle • esc close"))
+
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, pickerStyle.Render(b.String()))
+}
+
+func truncate(s string, width int) string {
+	r := []rune(s)
+	if len(r) <= width {
+		return s
+	}
+
+	return string(r[:width-1]) + "…"
+}
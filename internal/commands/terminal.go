@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"os"
+	"sync"
+)
+
+// terminal is the program's output, shared with commands that write escape
+// sequences themselves so their writes never land inside a frame. It stays
+// an *os.File underneath for bubbletea to size and restore.
+type terminal struct {
+	*os.File
+	mu sync.Mutex
+}
+
+func newTerminal(f *os.File) *terminal {
+	return &terminal{File: f}
+}
+
+func (t *terminal) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.File.Write(p)
+}
@@ -7,7 +7,10 @@ import (
 	"os/exec"
 	"regexp"
 	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/list"
@@ -29,15 +32,25 @@ var (
 )
 
 type TUIItem struct {
-	Title     string
-	FeedName  string
-	URL       string
-	ID        int
-	Read      bool
-	Favourite bool
+	Title       string
+	FeedName    string
+	URL         string
+	ID          int
+	Read        bool
+	Favourite   bool
+	Categories  []string
+	Tags        []string
+	Author      string
+	Content     string
+	PublishedAt time.Time
+	Score       int
+	AlsoIn      []string
 }
 
-func (i TUIItem) FilterValue() string { return fmt.Sprintf("%s||%s", i.Title, i.FeedName) }
+// FilterValue leads with the ID so the filter can find the whole item
+func (i TUIItem) FilterValue() string {
+	return strings.Join(slices.Concat([]string{strconv.Itoa(i.ID), i.Title, i.FeedName}, i.Categories, i.Tags), "||")
+}
 
 type model struct {
 	selectedArticle *int
@@ -47,8 +60,22 @@ type model struct {
 	list            list.Model
 	help            help.Model
 	viewport        viewport.Model
-	lastRead        *list.Item
-	lastReadIndex   int
+	// article is the rendered article in the viewport, before highlighting
+	article string
+	search  *articleSearch
+	// status is shown in the viewport footer, the list has its own
+	status    string
+	tagPicker *tagPicker
+	// linkPicker is open over the article view
+	linkPicker *linkPicker
+	sidebar    *sidebar
+	preview    *preview
+	// selected items, shared with the list delegate to mark them
+	selected     map[int]bool
+	selectAnchor int
+	journal      *journal
+	width        int
+	height       int
 }
 
 func (m model) Init() tea.Cmd {
@@ -61,28 +88,92 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		x, y := appStyle.GetFrameSize()
-
-		m.list.SetSize(msg.Width-x, msg.Height-y)
+		m.width, m.height = msg.Width-x, msg.Height-y
+		m.resize()
+		m.syncPreview()
 
 		m.viewport.Width = msg.Width - x
 		footerHeight := lipgloss.Height(m.viewportHelp())
 		m.viewport.Height = msg.Height - footerHeight
 
 		return m, nil
+
+	case tea.MouseMsg:
+		return updateMouse(msg, m)
+	}
+
+	// the pickers take the keys, while everything else still reaches the view
+	// under them once their input has had it to blink
+	keyMsg, isKey := msg.(tea.KeyMsg)
+	switch {
+	case m.tagPicker != nil && isKey:
+		return updateTagPicker(keyMsg, m)
+	case m.linkPicker != nil && isKey:
+		return updateLinkPicker(keyMsg, m)
+	}
+	pickerCmd := m.updatePickerInput(msg)
+
+	var (
+		next tea.Model
+		cmd  tea.Cmd
+	)
+	switch {
+	case m.selectedArticle != nil:
+		next, cmd = updateViewport(msg, m)
+		return next, tea.Batch(pickerCmd, cmd)
+	case m.preview != nil && m.preview.focused && m.previewShown():
+		next, cmd = updatePreview(msg, m)
+	case m.sidebar != nil && m.sidebar.focused:
+		next, cmd = updateSidebar(msg, m)
+	default:
+		next, cmd = updateList(msg, m)
+	}
+
+	// follow the list cursor in the preview pane
+	if nm, ok := next.(model); ok && nm.selectedArticle == nil && nm.tagPicker == nil {
+		nm.syncPreview()
+		next = nm
+	}
+
+	return next, tea.Batch(pickerCmd, cmd)
+}
+
+// updatePickerInput passes a message that isn't a key to the input of the open
+// picker, if any
+func (m model) updatePickerInput(msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	switch {
+	case m.tagPicker != nil:
+		m.tagPicker.input, cmd = m.tagPicker.input.Update(msg)
+	case m.linkPicker != nil:
+		m.linkPicker.input, cmd = m.linkPicker.input.Update(msg)
 	}
 
-	if m.selectedArticle != nil {
-		return updateViewport(msg, m)
+	return cmd
+}
+
+// resize fits the list beside the sidebar and preview panes when open
+func (m *model) resize() {
+	width, height := m.width, m.height
+	if m.sidebar != nil {
+		width -= sidebarWidth + 1
+	}
+	if m.previewShown() {
+		width, height = m.resizePreview(width, height)
 	}
 
-	return updateList(msg, m)
+	m.list.SetSize(width, height)
 }
 
 func (m model) View() string {
 	var s string
 
-	if m.selectedArticle == nil {
-		s = listView(m)
+	if m.tagPicker != nil {
+		s = tagPickerView(m)
+	} else if m.linkPicker != nil {
+		s = linkPickerView(m)
+	} else if m.selectedArticle == nil {
+		s = mainView(m)
 	} else {
 		s = viewportView(m)
 	}
@@ -91,15 +182,41 @@ func (m model) View() string {
 }
 
 func (m model) OpenLink(url string) tea.Cmd {
-	hasOpener := false
-	for _, o := range m.cfg.Openers {
+	cmd, ok := m.openWith(m.cfg.Openers, url)
+	if ok {
+		return cmd
+	}
+
+	// if no opener, default to browser
+	err := m.OpenInBrowser(url)
+	if err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+// OpenEnclosure opens media with the first matching media opener, falling
+// back to the regular openers.
+func (m model) OpenEnclosure(url string) tea.Cmd {
+	cmd, ok := m.openWith(m.cfg.MediaOpeners, url)
+	if ok {
+		return cmd
+	}
+
+	return m.OpenLink(url)
+}
+
+// openWith runs the first opener whose regex matches url, reporting whether
+// one matched.
+func (m model) openWith(openers []config.Opener, url string) (tea.Cmd, bool) {
+	for _, o := range openers {
 		match, err := regexp.MatchString(o.Regex, url)
 		if err != nil {
-			return tea.Quit
+			return tea.Quit, true
 		}
 
 		if match {
-			hasOpener = true
 			cmdStr := fmt.Sprintf(o.Cmd, url)
 			parts := strings.Fields(cmdStr)
 			cmd := exec.Command(parts[0], parts[1:]...)
@@ -108,7 +225,7 @@ func (m model) OpenLink(url string) tea.Cmd {
 				return tea.ExecProcess(cmd, func(err error) tea.Msg {
 					log.Println("OpenLink: takeover exec:", err)
 					return nil
-				})
+				}), true
 			} else {
 				return func() tea.Msg {
 					if err := cmd.Run(); err != nil {
@@ -118,20 +235,12 @@ func (m model) OpenLink(url string) tea.Cmd {
 						}
 					}
 					return nil
-				}
+				}, true
 			}
 		}
 	}
 
-	// if no opener, default to browser
-	if !hasOpener {
-		err := m.OpenInBrowser(url)
-		if err != nil {
-			log.Println(err)
-		}
-	}
-
-	return nil
+	return nil, false
 }
 
 func (m model) OpenInBrowser(url string) error {
@@ -163,14 +272,27 @@ func (m model) OpenInBrowser(url string) error {
 }
 
 func ItemToTUIItem(i store.Item) TUIItem {
-	return TUIItem{
-		ID:        i.ID,
-		FeedName:  i.FeedName,
-		Title:     i.Title,
-		URL:       i.Link,
-		Read:      i.Read(),
-		Favourite: i.Favourite,
+	t := TUIItem{
+		ID:         i.ID,
+		FeedName:   i.FeedName,
+		Title:      i.Title,
+		URL:        i.Link,
+		Read:       i.Read(),
+		Favourite:  i.Favourite,
+		Categories: i.Categories,
+		Tags:       i.Tags,
+		Author:     i.Author,
+		Content:    i.Content,
+		Score:      i.Score,
+		AlsoIn:     i.AlsoIn,
+	}
+
+	t.PublishedAt = i.PublishedAt
+	if t.PublishedAt.IsZero() {
+		t.PublishedAt = i.CreatedAt
 	}
+
+	return t
 }
 
 func (c *Commands) TUI() error {
@@ -184,6 +306,14 @@ func (c *Commands) TUI() error {
 		defer f.Close()
 	}
 
+	// the scoring and dedupe config may have changed since the last fetch
+	if err := c.rescore(); err != nil {
+		log.Println("TUI:", err)
+	}
+	if err := c.clusterDuplicates(); err != nil {
+		log.Println("TUI:", err)
+	}
+
 	its, err := c.GetAllFeeds()
 	if err != nil {
 		return fmt.Errorf("commands List: %w", err)
@@ -209,6 +339,13 @@ func (c *Commands) TUI() error {
 		}
 	}
 
+	if c.config.Images.Enabled {
+		c.images, err = newImageCache(c.config)
+		if err != nil {
+			return fmt.Errorf("[commands.go] TUI: %w", err)
+		}
+	}
+
 	items := convertItems(its)
 
 	es := []string{}
@@ -239,7 +376,8 @@ func Render(items []list.Item, cmds *Commands, errors []string, cfg *config.Conf
 
 	appStyle.Height(height)
 
-	l := list.New(items, itemDelegate{theme: cfg.Theme}, defaultWidth, height)
+	selected := make(map[int]bool)
+	l := list.New(items, itemDelegate{theme: cfg.Theme, selected: selected}, defaultWidth, height)
 	l.SetShowStatusBar(false)
 	l.Title = defaultTitle
 	l.Styles.Title = titleStyle.
@@ -251,11 +389,12 @@ func Render(items []list.Item, cmds *Commands, errors []string, cfg *config.Conf
 
 	l.FilterInput.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(cfg.Theme.FilterColor))
 
-	l.Filter = CustomFilter(cfg.Filtering)
+	l.Filter = CustomFilter(cfg.Filtering, items)
 
 	ListKeyMap.SetOverrides(&l)
 
 	vp := viewport.New(78, height)
+	vp.KeyMap = ViewportKeyMap.ViewportKeys()
 
 	m := model{
 		cfg:      cfg,
@@ -264,9 +403,20 @@ func Render(items []list.Item, cmds *Commands, errors []string, cfg *config.Conf
 		help:     help.New(),
 		list:     l,
 		viewport: vp,
+		selected: selected,
+		journal:  &journal{},
+	}
+	if cfg.Preview.Enabled {
+		m.preview = newPreview(cfg.Preview)
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithOutput(cmds.out)}
+	// the mouse is off by default, as taking it stops the terminal selecting text
+	if cfg.Mouse {
+		opts = append(opts, tea.WithMouseCellMotion())
 	}
 
-	prog := tea.NewProgram(m, tea.WithAltScreen())
+	prog := tea.NewProgram(m, opts...)
 
 	return prog, nil
 }
@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestPickersPassOnMessages(t *testing.T) {
+	id := 1
+	input := textinput.New()
+	input.Focus()
+	m := model{selectedArticle: &id, linkPicker: &linkPicker{input: input}}
+
+	// a download started before the picker opened keeps reporting
+	ch := make(chan downloadUpdate, 1)
+	next, cmd := m.Update(downloadUpdate{status: "50%", ch: ch})
+	m = next.(model)
+	test.Equal(t, "50%", m.status, "status under the link picker")
+	test.Equal(t, true, m.linkPicker != nil, "link picker still open")
+	test.Equal(t, true, cmd != nil, "waits for more progress")
+
+	ch <- downloadUpdate{status: "Downloaded", done: true}
+	msg, ok := cmd().(downloadUpdate)
+	test.Equal(t, true, ok, "progress read from the download")
+	test.Equal(t, "Downloaded", msg.status, "next progress")
+
+	// keys still go to the picker
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	m = next.(model)
+	test.Equal(t, "g", m.linkPicker.input.Value(), "typed in the link picker")
+	test.Equal(t, "50%", m.status, "status left alone by keys")
+
+	m = selectionModel()
+	m.tagPicker = &tagPicker{input: input}
+	next, cmd = m.Update(downloadUpdate{status: "50%", ch: ch})
+	test.Equal(t, true, next.(model).tagPicker != nil, "tag picker still open")
+	test.Equal(t, true, cmd != nil, "list under the tag picker updated")
+}
@@ -1,9 +1,13 @@
 package commands
 
 import (
+	"strings"
+
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/guyfedwards/nom/v2/internal/termimg"
 )
 
 func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
@@ -12,10 +16,59 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		cmds []tea.Cmd
 	)
 
+	if m.search != nil && m.search.typing {
+		return updateSearch(msg, m)
+	}
+
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.help.Width = msg.Width
 
+	case statusUpdate:
+		m.status = msg.status
+	case downloadUpdate:
+		m.status = msg.status
+		if !msg.done {
+			cmds = append(cmds, waitForDownload(msg.ch))
+		}
+	case noteSaved:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			break
+		}
+
+		content, err := m.commands.renderArticle(*m.selectedArticle)
+		if err != nil {
+			m.status = err.Error()
+			break
+		}
+		m.setArticle(content)
+		m.status = "Note saved."
+	case fullTextFetched:
+		if msg.err != nil {
+			m.status = msg.err.Error()
+			break
+		}
+
+		content, err := m.commands.renderArticle(*m.selectedArticle)
+		if err != nil {
+			m.status = err.Error()
+			break
+		}
+		m.setArticle(content)
+		m.status = "Fetched full text."
+	case imagesFetched:
+		// the article may have moved on while they loaded
+		if msg.drawn == 0 || msg.id != *m.selectedArticle {
+			break
+		}
+
+		content, err := m.commands.renderArticle(*m.selectedArticle)
+		if err != nil {
+			m.status = err.Error()
+			break
+		}
+		m.setArticle(content)
 	case tea.ResumeMsg:
 		return m, nil
 	case tea.KeyMsg:
@@ -28,7 +81,21 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, ViewportKeyMap.GotoEnd):
 			m.viewport.GotoBottom()
 
+		case key.Matches(msg, ViewportKeyMap.ClearSearch) && m.search != nil:
+			m.clearSearch()
+			return m, nil
+
+		case key.Matches(msg, ViewportKeyMap.Search), key.Matches(msg, ViewportKeyMap.SearchBack):
+			m.status = ""
+			return m, m.startSearch(key.Matches(msg, ViewportKeyMap.SearchBack))
+
+		case key.Matches(msg, ViewportKeyMap.NextMatch), key.Matches(msg, ViewportKeyMap.PrevMatch):
+			m.nextMatch(key.Matches(msg, ViewportKeyMap.NextMatch))
+			return m, nil
+
 		case key.Matches(msg, ViewportKeyMap.Escape):
+			m.search = nil
+			m.status = ""
 			// reset cursor if last post is read and quit
 			index := m.list.Index()
 			length := len(m.list.Items())
@@ -49,12 +116,79 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			cmd = m.OpenLink(it.URL)
 			cmds = append(cmds, cmd)
 
+		case key.Matches(msg, ViewportKeyMap.OpenEnclosure):
+			current, err := m.commands.store.GetItemByID(*m.selectedArticle)
+			if err != nil {
+				return m, nil
+			}
+			if len(current.Enclosures) == 0 {
+				m.status = "No enclosures."
+				return m, nil
+			}
+
+			m.status = "Opening..."
+			cmds = append(cmds, m.OpenEnclosure(current.Enclosures[0].URL))
+
+		case key.Matches(msg, ViewportKeyMap.Download):
+			current, err := m.commands.store.GetItemByID(*m.selectedArticle)
+			if err != nil {
+				return m, nil
+			}
+			if len(current.Enclosures) == 0 {
+				m.status = "No enclosures."
+				return m, nil
+			}
+
+			m.status = "Downloading..."
+			cmds = append(cmds, m.DownloadEnclosure(current.Enclosures[0]))
+
+		case key.Matches(msg, ViewportKeyMap.Tag):
+			return m.openTagPicker(*m.selectedArticle)
+
+		case key.Matches(msg, ViewportKeyMap.ReadLater):
+			return m, m.toggleReadLater(*m.selectedArticle)
+
+		case key.Matches(msg, ViewportKeyMap.FullText):
+			m.status = "Fetching full text..."
+			cmds = append(cmds, m.FetchFullText(*m.selectedArticle))
+
+		case key.Matches(msg, ViewportKeyMap.YankURL), key.Matches(msg, ViewportKeyMap.YankTitle), key.Matches(msg, ViewportKeyMap.YankMarkdown):
+			current, err := m.commands.store.GetItemByID(*m.selectedArticle)
+			if err != nil {
+				m.status = err.Error()
+				break
+			}
+
+			cmds = append(cmds, m.commands.yank([]TUIItem{ItemToTUIItem(current)}, viewportYankFormat(msg)))
+
+		case key.Matches(msg, ViewportKeyMap.Links):
+			return m.openLinkPicker("")
+
+		case key.Matches(msg, ViewportKeyMap.Note):
+			return m, m.editNote(*m.selectedArticle)
+
+		case key.Matches(msg, ViewportKeyMap.Undo), key.Matches(msg, ViewportKeyMap.Redo):
+			m.status = m.undo(key.Matches(msg, ViewportKeyMap.Redo))
+
+			index := m.list.Index()
+			cmds = append(cmds, m.UpdateList())
+			m.selectItem(*m.selectedArticle, index)
+
+			content, err := m.commands.renderArticle(*m.selectedArticle)
+			if err != nil {
+				m.status = err.Error()
+				break
+			}
+			m.setArticle(content)
+
 		case key.Matches(msg, ViewportKeyMap.Favourite):
 			current, err := m.commands.store.GetItemByID(*m.selectedArticle)
 			if err != nil {
 				return m, nil
 			}
-			err = m.commands.store.ToggleFavourite(current.ID)
+			err = m.record("favourite", []int{current.ID}, func() error {
+				return m.commands.store.ToggleFavourite(current.ID)
+			})
 			if err != nil {
 				return m, tea.Quit
 			}
@@ -67,35 +201,27 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			if err != nil {
 				return m, nil
 			}
-			err = m.commands.store.ToggleRead(current.ID)
+			err = m.record("read", []int{current.ID}, func() error {
+				return m.commands.toggleRead(current.ID)
+			})
 			if err != nil {
 				return m, tea.Quit
 			}
 
 			if !m.commands.config.ShowRead {
+				// read drops out of the list, so next goes to what follows it,
+				// and unread comes back under the cursor
 				index := m.list.Index()
-
-				if m.lastRead != nil && current.ID == (*m.lastRead).(TUIItem).ID {
-					// un-read re-add post back to list
-					m.list.InsertItem(index, *m.lastRead)
-					m.lastReadIndex = index
-					m.lastRead = nil
-				} else {
-					// remove post and store backup for un-read
-					items := m.list.Items()
-					item := items[index]
-					m.list.RemoveItem(index)
-					m.lastReadIndex = index
-					m.lastRead = &item
-				}
+				cmds = append(cmds, m.UpdateList())
+				m.selectItem(current.ID, index)
 			}
 
 			// trigger refresh to update read indication
-			content, err := m.commands.GetGlamourisedArticle(*m.selectedArticle)
+			content, err := m.commands.renderArticle(*m.selectedArticle)
 			if err != nil {
 				return m, tea.Quit
 			}
-			m.viewport.SetContent(content)
+			m.setArticle(content)
 
 		case key.Matches(msg, ViewportKeyMap.Prev):
 			navIndex := m.getPrevIndex()
@@ -108,14 +234,16 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			item := items[navIndex]
 			id := item.(TUIItem).ID
 			m.selectedArticle = &id
+			m.search = nil
 
-			content, err := m.commands.GetGlamourisedArticle(*m.selectedArticle)
+			content, err := m.openArticle(*m.selectedArticle)
 			if err != nil {
 				return m, tea.Quit
 			}
 
-			m.viewport.SetContent(content)
+			m.setArticle(content)
 			m.viewport.GotoTop()
+			cmds = append(cmds, m.fetchImages(id))
 			if m.commands.config.AutoRead && !m.commands.config.ShowRead {
 				m.list.RemoveItem(m.list.Index())
 			}
@@ -131,14 +259,16 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			item := items[navIndex]
 			id := item.(TUIItem).ID
 			m.selectedArticle = &id
+			m.search = nil
 
-			content, err := m.commands.GetGlamourisedArticle(*m.selectedArticle)
+			content, err := m.openArticle(*m.selectedArticle)
 			if err != nil {
 				return m, tea.Quit
 			}
 
-			m.viewport.SetContent(content)
+			m.setArticle(content)
 			m.viewport.GotoTop()
+			cmds = append(cmds, m.fetchImages(id))
 			if m.commands.config.AutoRead && !m.commands.config.ShowRead {
 				m.list.RemoveItem(m.list.Index())
 			}
@@ -153,6 +283,11 @@ func updateViewport(msg tea.Msg, m model) (tea.Model, tea.Cmd) {
 			} else {
 				m.viewport.Height = m.viewport.Height + lipgloss.Height(m.help.FullHelpView(ViewportKeyMap.FullHelp())) - lipgloss.Height(m.help.ShortHelpView(ViewportKeyMap.ShortHelp()))
 			}
+
+		case len(msg.Runes) == 1 && msg.Runes[0] >= '1' && msg.Runes[0] <= '9' && !msg.Alt:
+			// typing a link's number starts the picker with it, unless the
+			// number has been bound to something else above
+			return m.openLinkPicker(string(msg.Runes))
 		}
 	}
 
@@ -214,9 +349,30 @@ func (m *model) getPrevIndex() int {
 }
 
 func viewportView(m model) string {
-	return m.viewport.View() + "\n" + m.viewportHelp()
+	// images drawn over the rows above them are left out until all their
+	// rows are in view
+	lines := strings.Split(m.viewport.View(), "\n")
+	for i, line := range lines {
+		if rows, ok := termimg.OverlayRows(line); ok && i < rows {
+			lines[i] = termimg.HideOverlay(line)
+		}
+	}
+
+	return strings.Join(lines, "\n") + "\n" + m.viewportHelp()
 }
 
 func (m model) viewportHelp() string {
-	return helpStyle.Render(m.help.View(ViewportKeyMap))
+	if m.search != nil && m.search.typing {
+		return helpStyle.Render(m.search.input.View() + "  " + m.search.count())
+	}
+
+	help := m.help.View(ViewportKeyMap)
+	if m.status != "" && !m.help.ShowAll {
+		help = m.status + "  " + help
+	}
+	if m.search != nil && !m.help.ShowAll {
+		help = m.search.status() + "  " + help
+	}
+
+	return helpStyle.Render(help)
 }
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+)
+
+// applyView keeps the items matching a saved search
+func applyView(items []store.Item, v config.View, now time.Time) ([]store.Item, error) {
+	since, until, err := v.Range(now)
+	if err != nil {
+		return nil, fmt.Errorf("applyView: %w", err)
+	}
+
+	var is []store.Item
+	for _, item := range items {
+		if matchesView(item, v, since, until) {
+			is = append(is, item)
+		}
+	}
+
+	return is, nil
+}
+
+func matchesView(item store.Item, v config.View, since time.Time, until time.Time) bool {
+	if !config.MatchFeed(v.Feeds, item.FeedName, item.FeedURL) {
+		return false
+	}
+
+	if !config.MatchAny(v.Tags, slices.Concat(item.Categories, item.Tags)) {
+		return false
+	}
+
+	if v.Read != nil && *v.Read != item.Read() {
+		return false
+	}
+
+	if v.Favourite != nil && *v.Favourite != item.Favourite {
+		return false
+	}
+
+	published := item.PublishedAt
+	if published.IsZero() {
+		published = item.CreatedAt
+	}
+	if !since.IsZero() && published.Before(since) {
+		return false
+	}
+	if !until.IsZero() && published.After(until) {
+		return false
+	}
+
+	if v.Text != "" {
+		text := strings.ToLower(v.Text)
+		if !strings.Contains(strings.ToLower(item.Title), text) && !strings.Contains(strings.ToLower(item.Content), text) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/store"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestApplyView(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	unread := false
+
+	items := []store.Item{
+		{ID: 1, FeedName: "Go Weekly", Title: "Generics in practice", Tags: []string{"golang"}, PublishedAt: now.AddDate(0, 0, -1)},
+		{ID: 2, FeedName: "go weekly", Title: "Old news", Categories: []string{"Golang"}, PublishedAt: now.AddDate(0, 0, -30)},
+		{ID: 3, FeedName: "Go Weekly", Title: "Already read", Tags: []string{"golang"}, PublishedAt: now.AddDate(0, 0, -2), ReadAt: now},
+		{ID: 4, FeedName: "Rust Weekly", Title: "Generics in Rust", Tags: []string{"golang"}, PublishedAt: now.AddDate(0, 0, -1)},
+		{ID: 5, FeedName: "Go Weekly", Title: "Tooling", Content: "<p>More on generics</p>", PublishedAt: now.AddDate(0, 0, -1), Favourite: true},
+	}
+
+	ids := func(is []store.Item) string {
+		out := []int{}
+		for _, i := range is {
+			out = append(out, i.ID)
+		}
+		return fmt.Sprint(out)
+	}
+
+	is, err := applyView(items, config.View{Name: "go", Feeds: []string{"go weekly"}, Tags: []string{"golang"}, Read: &unread, Since: "7d"}, now)
+	test.HandleError(t, err)
+	test.Equal(t, "[1]", ids(is), "feed, tag, read state and since should all apply")
+
+	is, err = applyView(items, config.View{Name: "generics", Text: "GENERICS"}, now)
+	test.HandleError(t, err)
+	test.Equal(t, "[1 4 5]", ids(is), "text should match title or content")
+
+	favourite := true
+	is, err = applyView(items, config.View{Name: "faves", Favourite: &favourite, Until: "2024-03-09"}, now)
+	test.HandleError(t, err)
+	test.Equal(t, "[5]", ids(is), "favourite and until should apply")
+
+	_, err = applyView(items, config.View{Name: "bad", Since: "last tuesday"}, now)
+	if err == nil {
+		t.Fatal("expected an error for an invalid since")
+	}
+}
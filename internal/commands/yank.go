@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// yankFormat is how an item is copied to the clipboard
+type yankFormat int
+
+const (
+	yankURL yankFormat = iota
+	yankTitle
+	yankMarkdown
+)
+
+var mdLinkTextEscaper = strings.NewReplacer(`\`, `\\`, `[`, `\[`, `]`, `\]`)
+
+func listYankFormat(msg tea.KeyMsg) yankFormat {
+	switch {
+	case key.Matches(msg, ListKeyMap.YankTitle):
+		return yankTitle
+	case key.Matches(msg, ListKeyMap.YankMarkdown):
+		return yankMarkdown
+	default:
+		return yankURL
+	}
+}
+
+func viewportYankFormat(msg tea.KeyMsg) yankFormat {
+	switch {
+	case key.Matches(msg, ViewportKeyMap.YankTitle):
+		return yankTitle
+	case key.Matches(msg, ViewportKeyMap.YankMarkdown):
+		return yankMarkdown
+	default:
+		return yankURL
+	}
+}
+
+func formatYank(items []TUIItem, format yankFormat) string {
+	lines := make([]string, 0, len(items))
+	for _, it := range items {
+		switch format {
+		case yankTitle:
+			lines = append(lines, it.Title+"\n"+it.URL)
+		case yankMarkdown:
+			lines = append(lines, fmt.Sprintf("[%s](%s)", mdLinkTextEscaper.Replace(it.Title), it.URL))
+		default:
+			lines = append(lines, it.URL)
+		}
+	}
+
+	sep := "\n"
+	if format == yankTitle {
+		sep = "\n\n"
+	}
+
+	return strings.Join(lines, sep)
+}
+
+func yankStatus(n int, format yankFormat) string {
+	what, whats := "URL", "URLs"
+	switch format {
+	case yankTitle:
+		what, whats = "title and URL", "titles and URLs"
+	case yankMarkdown:
+		what, whats = "markdown link", "markdown links"
+	}
+
+	if n > 1 {
+		return fmt.Sprintf("Copied %d %s.", n, whats)
+	}
+
+	return fmt.Sprintf("Copied %s.", what)
+}
+
+func (c Commands) yank(items []TUIItem, format yankFormat) tea.Cmd {
+	return c.copyToClipboard(formatYank(items, format), yankStatus(len(items), format))
+}
+
+// yankList copies the selected items, or the one under the cursor
+func (m *model) yankList(format yankFormat) tea.Cmd {
+	var items []TUIItem
+	for _, item := range m.list.Items() {
+		if it := item.(TUIItem); m.selected[it.ID] {
+			items = append(items, it)
+		}
+	}
+
+	clear(m.selected)
+
+	if len(items) == 0 {
+		item, ok := m.list.SelectedItem().(TUIItem)
+		if !ok {
+			return m.list.NewStatusMessage("No item selected.")
+		}
+		items = append(items, item)
+	}
+
+	return m.commands.yank(items, format)
+}
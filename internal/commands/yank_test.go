@@ -0,0 +1,26 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFormatYank(t *testing.T) {
+	items := []TUIItem{
+		{Title: "Go [1.22] released", URL: "https://go.dev/blog/go1.22"},
+		{Title: "Range over func", URL: "https://go.dev/blog/range-functions"},
+	}
+
+	test.Equal(t, "https://go.dev/blog/go1.22", formatYank(items[:1], yankURL), "url")
+	test.Equal(t, "Go [1.22] released\nhttps://go.dev/blog/go1.22", formatYank(items[:1], yankTitle), "title and url")
+	test.Equal(t, `[Go \[1.22\] released](https://go.dev/blog/go1.22)`, formatYank(items[:1], yankMarkdown), "brackets are escaped")
+	test.Equal(t, "https://go.dev/blog/go1.22\nhttps://go.dev/blog/range-functions", formatYank(items, yankURL), "one per line")
+
+	test.Equal(t, "Copied 2 markdown links.", yankStatus(2, yankMarkdown), "status")
+}
+
+func TestOSC52(t *testing.T) {
+	test.Equal(t, "\x1b]52;c;aGk=\x07", osc52("hi"), "osc 52")
+	test.Equal(t, "\x1bPtmux;\x1b\x1b]52;c;aGk=\x07\x1b\\", tmuxPassthrough(osc52("hi")), "wrapped for tmux")
+}
@@ -22,7 +22,11 @@ func getMinifluxFeeds(config *MinifluxBackend) ([]Feed, error) {
 	var ret []Feed
 
 	for _, f := range feeds {
-		ret = append(ret, Feed{URL: f.FeedURL})
+		feed := Feed{URL: f.FeedURL}
+		if f.Category != nil {
+			feed.Group = f.Category.Title
+		}
+		ret = append(ret, feed)
 	}
 
 	return ret, nil
@@ -102,7 +106,11 @@ func getFreshRSSFeeds(config *FreshRSSBackend) ([]Feed, error) {
 		if config.PrefixCats {
 			name = f.GetCats()
 		}
-		ret = append(ret, Feed{URL: f.URL, Name: name})
+		group := ""
+		if len(f.Categories) > 0 {
+			group = f.Categories[0].Label
+		}
+		ret = append(ret, Feed{URL: f.URL, Name: name, Group: group})
 	}
 
 	return ret, nil
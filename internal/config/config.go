@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -17,11 +20,63 @@ var (
 	DefaultConfigDirName  = "nom"
 	DefaultConfigFileName = "config.yml"
 	DefaultDatabaseName   = "nom.db"
+	DefaultPodcastDirName = "podcasts"
+	DefaultImageDirName   = "images"
+)
+
+// Feed types. An empty type is treated as a regular RSS/Atom feed.
+const (
+	FeedTypeRSS    = ""
+	FeedTypeScrape = "scrape"
+	// Mail feeds read newsletters from a local folder, the url is its path
+	FeedTypeMaildir = "maildir"
+	FeedTypeMbox    = "mbox"
+)
+
+// Preview pane positions
+const (
+	PreviewRight  = "right"
+	PreviewBottom = "bottom"
 )
 
 type Feed struct {
-	URL  string `yaml:"url"`
-	Name string `yaml:"name,omitempty"`
+	URL    string         `yaml:"url"`
+	Name   string         `yaml:"name,omitempty"`
+	Type   string         `yaml:"type,omitempty"`
+	Scrape *ScrapeOptions `yaml:"scrape,omitempty"`
+	// FullText fetches the linked article for every new item, for feeds
+	// that only carry a summary
+	FullText bool `yaml:"fulltext,omitempty"`
+	// Group puts the feed in a folder in the sidebar, nested with /
+	Group string `yaml:"group,omitempty"`
+}
+
+// InGroup reports whether the feed is in group or one nested under it
+func (f Feed) InGroup(group string) bool {
+	g := f.GroupPath()
+	return g == group || strings.HasPrefix(g, group+"/")
+}
+
+// GroupPath is the feed's group without empty parts, so "a//b/" is "a/b"
+func (f Feed) GroupPath() string {
+	return strings.Join(strings.FieldsFunc(f.Group, func(r rune) bool { return r == '/' }), "/")
+}
+
+// IsMail reports whether the feed is read from a local mail folder rather
+// than fetched.
+func (f Feed) IsMail() bool {
+	return f.Type == FeedTypeMaildir || f.Type == FeedTypeMbox
+}
+
+// ScrapeOptions holds the CSS selectors used to turn an HTML page into feed
+// items. Title, Link, Date and Content are relative to each Item match.
+type ScrapeOptions struct {
+	Item       string `yaml:"item"`
+	Title      string `yaml:"title"`
+	Link       string `yaml:"link,omitempty"`
+	Date       string `yaml:"date,omitempty"`
+	DateFormat string `yaml:"dateFormat,omitempty"`
+	Content    string `yaml:"content,omitempty"`
 }
 
 type MinifluxBackend struct {
@@ -56,6 +111,46 @@ type Theme struct {
 	ReadIcon          string `yaml:"readIcon,omitempty"`
 }
 
+type ReadLaterConfig struct {
+	// FetchFullText stores the linked article when an item is queued, so it
+	// can be read offline
+	FetchFullText bool `yaml:"fetchfulltext"`
+}
+
+// PreviewConfig shows the selected article in a pane beside or below the list
+type PreviewConfig struct {
+	// Enabled opens the preview pane at start, it can be toggled with P
+	Enabled bool `yaml:"enabled"`
+	// Position is right, the default, or bottom
+	Position string `yaml:"position,omitempty"`
+	// Size is the percentage of the screen given to the list, default 50
+	Size int `yaml:"size,omitempty"`
+	// MinWidth is the narrowest terminal the pane is shown on the right
+	// in, default 100
+	MinWidth int `yaml:"minwidth,omitempty"`
+}
+
+// ImagesConfig draws images in the article view, in place of their links
+type ImagesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Protocol is auto, the default, or kitty, iterm, sixel or halfblocks
+	Protocol string `yaml:"protocol,omitempty"`
+	// MaxHeight is the most rows an image takes, default 20
+	MaxHeight int `yaml:"maxheight,omitempty"`
+	// CacheDir is where images are downloaded to, by default nom's
+	// directory in the user cache directory
+	CacheDir string `yaml:"cachedir,omitempty"`
+}
+
+var imageProtocols = []string{"", "auto", "kitty", "iterm", "sixel", "halfblocks"}
+
+type DedupeConfig struct {
+	// Enabled collapses the same story from several feeds into one item
+	Enabled bool `yaml:"enabled"`
+	// Threshold is how alike titles must be, from 0 to 1, default 0.7
+	Threshold float64 `yaml:"threshold,omitempty"`
+}
+
 type FilterConfig struct {
 	DefaultIncludeFeedName bool `yaml:"defaultIncludeFeedName"`
 }
@@ -64,22 +159,42 @@ type FilterConfig struct {
 type Config struct {
 	ConfigPath     string
 	ShowFavourites bool `yaml:"showfavourites,omitempty"`
+	ShowReadLater  bool `yaml:"showreadlater,omitempty"`
 	Version        string
 	ConfigDir      string       `yaml:"-"`
+	ActiveView     string       `yaml:"-"`
+	ActiveGroup    string       `yaml:"-"`
+	ActiveFeed     string       `yaml:"-"`
 	Pager          string       `yaml:"pager,omitempty"`
 	Feeds          []Feed       `yaml:"feeds"`
 	Database       string       `yaml:"database"`
 	Ordering       string       `yaml:"ordering"`
 	Filtering      FilterConfig `yaml:"filtering"`
 	// Preview feeds are distinguished from Feeds because we don't want to inadvertenly write those into the config file.
-	PreviewFeeds    []Feed       `yaml:"previewfeeds,omitempty"`
-	Backends        *Backends    `yaml:"backends,omitempty"`
-	ShowRead        bool         `yaml:"showread,omitempty"`
-	AutoRead        bool         `yaml:"autoread,omitempty"`
-	Openers         []Opener     `yaml:"openers,omitempty"`
-	Theme           Theme        `yaml:"theme,omitempty"`
-	HTTPOptions     *HTTPOptions `yaml:"http,omitempty"`
-	RefreshInterval int          `yaml:"refreshinterval,omitempty"`
+	PreviewFeeds    []Feed          `yaml:"previewfeeds,omitempty"`
+	Backends        *Backends       `yaml:"backends,omitempty"`
+	ShowRead        bool            `yaml:"showread,omitempty"`
+	AutoRead        bool            `yaml:"autoread,omitempty"`
+	Openers         []Opener        `yaml:"openers,omitempty"`
+	MediaOpeners    []Opener        `yaml:"mediaopeners,omitempty"`
+	PodcastDir      string          `yaml:"podcastdir,omitempty"`
+	ReadLater       ReadLaterConfig `yaml:"readlater,omitempty"`
+	Views           []View          `yaml:"views,omitempty"`
+	Rules           []Rule          `yaml:"rules,omitempty"`
+	Scoring         []ScoreRule     `yaml:"scoring,omitempty"`
+	Dedupe          DedupeConfig    `yaml:"dedupe,omitempty"`
+	Preview         PreviewConfig   `yaml:"preview,omitempty"`
+	Images          ImagesConfig    `yaml:"images,omitempty"`
+	Keys            KeyConfig       `yaml:"keys,omitempty"`
+	Theme           Theme           `yaml:"theme,omitempty"`
+	HTTPOptions     *HTTPOptions    `yaml:"http,omitempty"`
+	RefreshInterval int             `yaml:"refreshinterval,omitempty"`
+	// Mouse turns on clicking and scrolling, which stops the terminal
+	// selecting text
+	Mouse bool `yaml:"mouse,omitempty"`
+	// LocalClipboard copies with wl-copy, xclip, xsel or pbcopy as well as
+	// OSC 52, for terminals without it
+	LocalClipboard bool `yaml:"localclipboard,omitempty"`
 }
 
 var DefaultTheme = Theme{
@@ -99,6 +214,10 @@ func (c *Config) ToggleShowFavourites() {
 	c.ShowFavourites = !c.ShowFavourites
 }
 
+func (c *Config) ToggleShowReadLater() {
+	c.ShowReadLater = !c.ShowReadLater
+}
+
 func updateConfigPathIfDir(configPath string) string {
 	stat, err := os.Stat(configPath)
 	if err == nil && stat.IsDir() {
@@ -146,6 +265,30 @@ func New(configPath string, pager string, previewFeeds []string, version string)
 	}, nil
 }
 
+// GetPodcastDir returns where enclosures are downloaded to, by default a
+// podcasts directory next to the config file.
+func (c *Config) GetPodcastDir() string {
+	if c.PodcastDir != "" {
+		return c.PodcastDir
+	}
+
+	return filepath.Join(c.ConfigDir, DefaultPodcastDirName)
+}
+
+// GetImageCacheDir returns where article images are downloaded to
+func (c *Config) GetImageCacheDir() string {
+	if c.Images.CacheDir != "" {
+		return c.Images.CacheDir
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(c.ConfigDir, DefaultImageDirName)
+	}
+
+	return filepath.Join(dir, DefaultConfigDirName, DefaultImageDirName)
+}
+
 func (c *Config) IsPreviewMode() bool {
 	return len(c.PreviewFeeds) > 0
 }
@@ -175,9 +318,55 @@ func (c *Config) Load() error {
 		c.Database = fileConfig.Database
 	}
 	c.Openers = fileConfig.Openers
+	c.MediaOpeners = fileConfig.MediaOpeners
+	c.PodcastDir = fileConfig.PodcastDir
 	c.ShowFavourites = fileConfig.ShowFavourites
+	c.ShowReadLater = fileConfig.ShowReadLater
+	c.ReadLater = fileConfig.ReadLater
+
+	for _, v := range fileConfig.Views {
+		if _, _, err := v.Range(time.Now()); err != nil {
+			return fmt.Errorf("config.Load: %w", err)
+		}
+	}
+	c.Views = fileConfig.Views
+
+	for _, r := range fileConfig.Rules {
+		if _, _, _, err := r.Regexes(); err != nil {
+			return fmt.Errorf("config.Load: %w", err)
+		}
+	}
+	c.Rules = fileConfig.Rules
+	c.Scoring = fileConfig.Scoring
+
+	if t := fileConfig.Dedupe.Threshold; t < 0 || t > 1 {
+		return fmt.Errorf("config.Load: dedupe threshold %v should be between 0 and 1", t)
+	}
+	c.Dedupe = fileConfig.Dedupe
+
+	switch fileConfig.Preview.Position {
+	case "", PreviewRight, PreviewBottom:
+	default:
+		return fmt.Errorf("config.Load: preview position %q should be %s or %s", fileConfig.Preview.Position, PreviewRight, PreviewBottom)
+	}
+	if s := fileConfig.Preview.Size; s < 0 || s > 100 {
+		return fmt.Errorf("config.Load: preview size %d should be between 0 and 100", s)
+	}
+	c.Preview = fileConfig.Preview
+
+	if !slices.Contains(imageProtocols, fileConfig.Images.Protocol) {
+		return fmt.Errorf("config.Load: image protocol %q should be one of %s", fileConfig.Images.Protocol, strings.Join(imageProtocols[1:], ", "))
+	}
+	if fileConfig.Images.MaxHeight < 0 {
+		return fmt.Errorf("config.Load: image maxheight %d should not be negative", fileConfig.Images.MaxHeight)
+	}
+	c.Images = fileConfig.Images
+
+	c.Keys = fileConfig.Keys
 	c.Filtering = fileConfig.Filtering
 	c.RefreshInterval = fileConfig.RefreshInterval
+	c.Mouse = fileConfig.Mouse
+	c.LocalClipboard = fileConfig.LocalClipboard
 
 	if fileConfig.HTTPOptions != nil {
 		if _, err := TLSVersion(fileConfig.HTTPOptions.MinTLSVersion); err != nil {
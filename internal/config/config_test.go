@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"time"
 
 	"gopkg.in/yaml.v3"
 
@@ -150,3 +151,47 @@ func TestConfigSetupDir(t *testing.T) {
 
 	cleanup()
 }
+
+func TestParseViewTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for in, want := range map[string]time.Time{
+		"2024-01-02": time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		"12h":        now.Add(-12 * time.Hour),
+		"7d":         time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC),
+		"2w":         time.Date(2024, 2, 25, 12, 0, 0, 0, time.UTC),
+	} {
+		have, err := ParseViewTime(in, now)
+		test.HandleError(t, err)
+		test.Equal(t, want, have, "bad time for "+in)
+	}
+
+	for _, in := range []string{"", "d", "soon", "-3d", "3y"} {
+		if _, err := ParseViewTime(in, now); err == nil {
+			t.Errorf("expected an error for %q", in)
+		}
+	}
+}
+
+func TestKeysUnmarshal(t *testing.T) {
+	var kc KeyConfig
+	err := yaml.Unmarshal([]byte("list:\n  read: x\n  open: [enter, l]\n  sort: []\n"), &kc)
+	test.HandleError(t, err)
+
+	test.Equal(t, 1, len(kc.List["read"]), "one key")
+	test.Equal(t, "x", kc.List["read"][0], "one key")
+	test.Equal(t, 2, len(kc.List["open"]), "list of keys")
+	test.Equal(t, 0, len(kc.List["sort"]), "unbound")
+}
+
+func TestMatch(t *testing.T) {
+	test.Equal(t, true, MatchFeed(nil, "Go Blog", "https://go.dev/blog/feed.atom"), "unset feeds match")
+	test.Equal(t, true, MatchFeed([]string{"hn", "go blog"}, "Go Blog", "https://go.dev/blog/feed.atom"), "name ignores case")
+	test.Equal(t, true, MatchFeed([]string{"https://go.dev/blog/feed.atom"}, "Go Blog", "https://go.dev/blog/feed.atom"), "url")
+	test.Equal(t, false, MatchFeed([]string{"hn"}, "Go Blog", "https://go.dev/blog/feed.atom"), "other feed")
+
+	test.Equal(t, true, MatchAny(nil, nil), "unset list matches")
+	test.Equal(t, true, MatchAny([]string{"rust", "GO"}, []string{"go", "release"}), "any entry, ignoring case")
+	test.Equal(t, false, MatchAny([]string{"rust"}, []string{"go"}), "no entry")
+	test.Equal(t, false, MatchAny([]string{"rust"}, nil), "nothing to match")
+}
@@ -0,0 +1,29 @@
+package config
+
+import "gopkg.in/yaml.v3"
+
+// KeyConfig remaps the actions of the list and article views, by the action
+// names `nom keys` prints
+type KeyConfig struct {
+	List     map[string]Keys `yaml:"list,omitempty"`
+	Viewport map[string]Keys `yaml:"viewport,omitempty"`
+}
+
+// Keys are the keys bound to an action, written as one key or a list. An
+// empty list unbinds the action.
+type Keys []string
+
+func (k *Keys) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*k = Keys{value.Value}
+		return nil
+	}
+
+	var keys []string
+	if err := value.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+
+	return nil
+}
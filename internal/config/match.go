@@ -0,0 +1,27 @@
+package config
+
+import (
+	"slices"
+	"strings"
+)
+
+// Views, rules and scoring rules match items the same way: every condition
+// that is set must match, and a list matches if any of its entries do.
+
+// MatchFeed reports whether feeds is unset or holds the feed, by its name
+// ignoring case or by its URL
+func MatchFeed(feeds []string, name string, url string) bool {
+	return len(feeds) == 0 || slices.ContainsFunc(feeds, func(f string) bool {
+		return strings.EqualFold(f, name) || f == url
+	})
+}
+
+// MatchAny reports whether want is unset or any of it is in have, ignoring
+// case
+func MatchAny(want []string, have []string) bool {
+	return len(want) == 0 || slices.ContainsFunc(want, func(w string) bool {
+		return slices.ContainsFunc(have, func(h string) bool {
+			return strings.EqualFold(w, h)
+		})
+	})
+}
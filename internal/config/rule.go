@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// Rule acts on newly fetched items
+type Rule struct {
+	Name    string      `yaml:"name"`
+	Match   RuleMatch   `yaml:"match"`
+	Actions RuleActions `yaml:"actions"`
+}
+
+type RuleMatch struct {
+	// Feeds are feed names or URLs
+	Feeds []string `yaml:"feeds,omitempty"`
+	// Title, Author and Content are case insensitive regexes
+	Title      string   `yaml:"title,omitempty"`
+	Author     string   `yaml:"author,omitempty"`
+	Content    string   `yaml:"content,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+}
+
+type RuleActions struct {
+	Read      bool     `yaml:"read,omitempty"`
+	Favourite bool     `yaml:"favourite,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	// Delete drops the item instead of storing it
+	Delete bool `yaml:"delete,omitempty"`
+	// Hook is run with the item link substituted for %s, like openers
+	Hook string `yaml:"hook,omitempty"`
+}
+
+// Regexes compiles the title, author and content conditions, nil if unset
+func (r Rule) Regexes() (title, author, content *regexp.Regexp, err error) {
+	compile := func(field, s string) (*regexp.Regexp, error) {
+		if s == "" {
+			return nil, nil
+		}
+		re, err := regexp.Compile("(?i)" + s)
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %s: %w", r.Name, field, err)
+		}
+		return re, nil
+	}
+
+	if title, err = compile("title", r.Match.Title); err != nil {
+		return
+	}
+	if author, err = compile("author", r.Match.Author); err != nil {
+		return
+	}
+	content, err = compile("content", r.Match.Content)
+	return
+}
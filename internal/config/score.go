@@ -0,0 +1,14 @@
+package config
+
+// ScoreRule adds Score to the items it matches, and can be negative to sink
+// them
+type ScoreRule struct {
+	Score int `yaml:"score"`
+	// Feeds are feed names or URLs
+	Feeds []string `yaml:"feeds,omitempty"`
+	// Keywords are looked for in the title and content, ignoring case
+	Keywords []string `yaml:"keywords,omitempty"`
+	Authors  []string `yaml:"authors,omitempty"`
+	// Tags match user tags and feed categories
+	Tags []string `yaml:"tags,omitempty"`
+}
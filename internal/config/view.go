@@ -0,0 +1,85 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// View is a saved search
+type View struct {
+	Name      string   `yaml:"name"`
+	Feeds     []string `yaml:"feeds,omitempty"`
+	Tags      []string `yaml:"tags,omitempty"`
+	Read      *bool    `yaml:"read,omitempty"`
+	Favourite *bool    `yaml:"favourite,omitempty"`
+	// Since and Until take a date (2006-01-02) or an age such as 12h, 7d or
+	// 2w
+	Since string `yaml:"since,omitempty"`
+	Until string `yaml:"until,omitempty"`
+	Text  string `yaml:"text,omitempty"`
+}
+
+// Range returns the publish dates the view is limited to, zero if unset
+func (v View) Range(now time.Time) (since time.Time, until time.Time, err error) {
+	if v.Since != "" {
+		since, err = ParseViewTime(v.Since, now)
+		if err != nil {
+			return since, until, fmt.Errorf("view %s: since: %w", v.Name, err)
+		}
+	}
+
+	if v.Until != "" {
+		until, err = ParseViewTime(v.Until, now)
+		if err != nil {
+			return since, until, fmt.Errorf("view %s: until: %w", v.Name, err)
+		}
+		// until a date includes the whole of that day
+		if _, err := time.Parse("2006-01-02", strings.TrimSpace(v.Until)); err == nil {
+			until = until.AddDate(0, 0, 1)
+		}
+	}
+
+	return since, until, nil
+}
+
+// ParseViewTime parses a date, or an age relative to now in hours, days or
+// weeks.
+func ParseViewTime(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+
+	if t, err := time.ParseInLocation("2006-01-02", s, now.Location()); err == nil {
+		return t, nil
+	}
+
+	if len(s) < 2 {
+		return time.Time{}, fmt.Errorf("invalid date or age %q", s)
+	}
+
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 0 {
+		return time.Time{}, fmt.Errorf("invalid date or age %q", s)
+	}
+
+	switch s[len(s)-1] {
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), nil
+	case 'd':
+		return now.AddDate(0, 0, -n), nil
+	case 'w':
+		return now.AddDate(0, 0, -7*n), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date or age %q", s)
+}
+
+func (c *Config) GetView(name string) (View, bool) {
+	for _, v := range c.Views {
+		if strings.EqualFold(v.Name, name) {
+			return v, true
+		}
+	}
+
+	return View{}, false
+}
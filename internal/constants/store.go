@@ -4,5 +4,8 @@ package constants
 const (
 	AscendingOrdering  = "asc"
 	DescendingOrdering = "desc"
-	DefaultOrdering    = AscendingOrdering
+	// ScoreOrdering puts the highest scored items first, newest first
+	// within a score
+	ScoreOrdering   = "score"
+	DefaultOrdering = AscendingOrdering
 )
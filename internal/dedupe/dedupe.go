@@ -0,0 +1,271 @@
+// Package dedupe finds the same story published by several feeds, either by
+// its link once tracking parameters are stripped, or by titles that are
+// nearly the same.
+//
+// Titles are compared by the Jaccard similarity of their words, and must
+// have the same numbers in them, so "Go 1.21" isn't "Go 1.22". Candidate
+// pairs are found with locality sensitive hashing over MinHash signatures,
+// which keeps this from comparing every pair of items.
+package dedupe
+
+import (
+	"hash/fnv"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultThreshold is the title similarity, from 0 to 1, above which two
+// items are the same story
+const DefaultThreshold = 0.7
+
+const (
+	numHashes = 64
+	bands     = 16
+	rows      = numHashes / bands
+	// titles with fewer words than this, such as "Release notes", are only
+	// matched by link
+	minTitleWords = 3
+)
+
+// Item is what is compared to find duplicates
+type Item struct {
+	ID    int
+	Feed  string
+	Link  string
+	Title string
+}
+
+// trackingParams are dropped from query strings, as are any starting utm_
+var trackingParams = map[string]bool{
+	"fbclid":  true,
+	"gclid":   true,
+	"yclid":   true,
+	"igshid":  true,
+	"mc_cid":  true,
+	"mc_eid":  true,
+	"mkt_tok": true,
+	"_hsenc":  true,
+	"_hsmi":   true,
+	"ref":     true,
+	"ref_src": true,
+	"source":  true,
+}
+
+// CanonicalURL normalises a link so copies of it compare equal: the scheme,
+// www., default ports, fragments, trailing slashes and tracking parameters
+// are dropped and the query is sorted.
+func CanonicalURL(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return link
+	}
+
+	host := strings.ToLower(u.Hostname())
+	host = strings.TrimPrefix(host, "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	q := u.Query()
+	for k := range q {
+		if trackingParams[strings.ToLower(k)] || strings.HasPrefix(strings.ToLower(k), "utm_") {
+			q.Del(k)
+		}
+	}
+
+	path := strings.TrimSuffix(u.EscapedPath(), "/")
+
+	canonical := host + path
+	if len(q) > 0 {
+		// Encode sorts by key
+		canonical += "?" + q.Encode()
+	}
+
+	return canonical
+}
+
+// title is a title broken into words, lowercased and without punctuation
+type title struct {
+	words   map[string]struct{}
+	numbers map[string]struct{}
+}
+
+func parseTitle(s string) title {
+	t := title{words: make(map[string]struct{}), numbers: make(map[string]struct{})}
+
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, f := range fields {
+		t.words[f] = struct{}{}
+		if strings.IndexFunc(f, unicode.IsDigit) >= 0 {
+			t.numbers[f] = struct{}{}
+		}
+	}
+
+	return t
+}
+
+// similarity is the Jaccard similarity of the words of two titles, or 0
+// when both have numbers and they differ
+func (t title) similarity(o title) float64 {
+	if len(t.numbers) > 0 && len(o.numbers) > 0 && !sameKeys(t.numbers, o.numbers) {
+		return 0
+	}
+
+	shared := 0
+	for w := range t.words {
+		if _, ok := o.words[w]; ok {
+			shared++
+		}
+	}
+
+	total := len(t.words) + len(o.words) - shared
+	if total == 0 {
+		return 0
+	}
+	return float64(shared) / float64(total)
+}
+
+func sameKeys(a, b map[string]struct{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// mix is the splitmix64 finaliser, used to derive the MinHash functions
+// from one word hash
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+type signature [numHashes]uint64
+
+func minhash(words map[string]struct{}) signature {
+	var sig signature
+	for i := range sig {
+		sig[i] = ^uint64(0)
+	}
+
+	for w := range words {
+		h := fnv.New64a()
+		h.Write([]byte(w))
+		s := h.Sum64()
+
+		for i := range sig {
+			if v := mix(s ^ mix(uint64(i)+1)); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+
+	return sig
+}
+
+// Similarity is how alike two titles are, from 0 to 1
+func Similarity(a, b string) float64 {
+	return parseTitle(a).similarity(parseTitle(b))
+}
+
+// Cluster groups items from different feeds that are the same story. It
+// returns the cluster of every item that has duplicates, keyed by item ID,
+// with the lowest ID in the cluster as the cluster ID.
+func Cluster(items []Item, threshold float64) map[int]int {
+	parent := make([]int, len(items))
+	for i := range parent {
+		parent[i] = i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	union := func(i, j int) {
+		if items[i].Feed == items[j].Feed {
+			return
+		}
+		if ri, rj := find(i), find(j); ri != rj {
+			parent[rj] = ri
+		}
+	}
+
+	links := make(map[string]int)
+	for i, it := range items {
+		if it.Link == "" {
+			continue
+		}
+		c := CanonicalURL(it.Link)
+		if j, ok := links[c]; ok {
+			union(j, i)
+		} else {
+			links[c] = i
+		}
+	}
+
+	titles := make([]title, len(items))
+	buckets := make(map[[rows + 1]uint64][]int)
+	for i, it := range items {
+		titles[i] = parseTitle(it.Title)
+		if len(titles[i].words) < minTitleWords {
+			continue
+		}
+
+		sig := minhash(titles[i].words)
+		for b := 0; b < bands; b++ {
+			var key [rows + 1]uint64
+			key[0] = uint64(b)
+			copy(key[1:], sig[b*rows:(b+1)*rows])
+			buckets[key] = append(buckets[key], i)
+		}
+	}
+
+	// candidates share a band, so similar pairs are very likely found
+	// without comparing every pair
+	for _, candidates := range buckets {
+		for x := 0; x < len(candidates); x++ {
+			for y := x + 1; y < len(candidates); y++ {
+				i, j := candidates[x], candidates[y]
+				if titles[i].similarity(titles[j]) >= threshold {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	for i := range items {
+		root := find(i)
+		groups[root] = append(groups[root], items[i].ID)
+	}
+
+	clusters := make(map[int]int)
+	for _, ids := range groups {
+		if len(ids) < 2 {
+			continue
+		}
+		sort.Ints(ids)
+		for _, id := range ids {
+			clusters[id] = ids[0]
+		}
+	}
+
+	return clusters
+}
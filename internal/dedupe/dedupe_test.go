@@ -0,0 +1,73 @@
+package dedupe
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	cases := map[string]string{
+		"https://www.Example.com/post/?utm_source=rss&utm_medium=feed": "example.com/post",
+		"http://example.com/post#comments":                             "example.com/post",
+		"https://example.com:443/post?b=2&a=1&fbclid=x":                "example.com/post?a=1&b=2",
+		"https://example.com:8080/post":                                "example.com:8080/post",
+		"not a url":                                                    "not a url",
+	}
+
+	for in, want := range cases {
+		test.Equal(t, want, CanonicalURL(in), "wrong canonical url for "+in)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	cases := []struct {
+		a, b string
+		same bool
+	}{
+		{"Go 1.22 is released!", "go 1.22 is released", true},
+		{"Go 1.22 is released", "Go 1.22 released", true},
+		{"OpenSSL fixes high severity vulnerability", "OpenSSL fixes high-severity vulnerability", true},
+		{"Go 1.22 is released", "Go 1.21 is released", false},
+		{"Weekly links #12", "Weekly links #13", false},
+		{"Why I write Go", "Why I write Rust", false},
+	}
+
+	for _, c := range cases {
+		got := Similarity(c.a, c.b) >= DefaultThreshold
+		test.Equal(t, c.same, got, fmt.Sprintf("%q and %q", c.a, c.b))
+	}
+}
+
+func TestCluster(t *testing.T) {
+	items := []Item{
+		{ID: 1, Feed: "go blog", Link: "https://go.dev/blog/go1.22", Title: "Go 1.22 is released!"},
+		{ID: 2, Feed: "hn", Link: "https://go.dev/blog/go1.22?utm_source=hn", Title: "Go 1.22 Released"},
+		{ID: 3, Feed: "lobsters", Link: "https://lobste.rs/s/abc", Title: "Go 1.22 is released"},
+		{ID: 4, Feed: "go blog", Link: "https://go.dev/blog/go1.21", Title: "Go 1.21 is released"},
+		{ID: 5, Feed: "go blog", Link: "https://go.dev/blog/go1.22#again", Title: "Go 1.22 is released, again"},
+		{ID: 6, Feed: "hn", Link: "https://example.com/a", Title: "Update"},
+		{ID: 7, Feed: "lobsters", Link: "https://example.com/b", Title: "Update"},
+	}
+
+	clusters := Cluster(items, DefaultThreshold)
+
+	got := ""
+	for _, it := range items {
+		got += fmt.Sprintf("%d:%d ", it.ID, clusters[it.ID])
+	}
+
+	// 5 has the same link as 1, but is from the same feed, so only joins
+	// through 2
+	test.Equal(t, "1:1 2:1 3:1 4:0 5:1 6:0 7:0 ", got, "wrong clusters")
+}
+
+func TestClusterSameFeed(t *testing.T) {
+	clusters := Cluster([]Item{
+		{ID: 1, Feed: "blog", Link: "https://example.com/a", Title: "Notes on the new release"},
+		{ID: 2, Feed: "blog", Link: "https://example.com/a", Title: "Notes on the new release"},
+	}, DefaultThreshold)
+
+	test.Equal(t, 0, len(clusters), "items from one feed shouldn't cluster")
+}
@@ -0,0 +1,198 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenTerm
+	tokenLParen
+	tokenRParen
+	tokenOr
+	tokenAnd
+	tokenNot
+)
+
+func (k tokenKind) String() string {
+	switch k {
+	case tokenEOF:
+		return "end of query"
+	case tokenLParen:
+		return "("
+	case tokenRParen:
+		return ")"
+	case tokenOr:
+		return "OR"
+	case tokenAnd:
+		return "AND"
+	case tokenNot:
+		return "NOT"
+	}
+	return "term"
+}
+
+type token struct {
+	kind tokenKind
+	pos  int
+	// terms only
+	field  string
+	value  string
+	quoted bool
+	regex  bool
+}
+
+type lexer struct {
+	input []rune
+	pos   int
+}
+
+func lex(input string) ([]token, error) {
+	l := &lexer{input: []rune(input)}
+
+	var tokens []token
+	for {
+		t, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, t)
+		if t.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (l *lexer) peek() rune {
+	if l.pos >= len(l.input) {
+		return 0
+	}
+	return l.input[l.pos]
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.input) && unicode.IsSpace(l.input[l.pos]) {
+		l.pos++
+	}
+
+	start := l.pos
+	if l.pos >= len(l.input) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	switch r := l.peek(); {
+	case r == '(':
+		l.pos++
+		return token{kind: tokenLParen, pos: start}, nil
+	case r == ')':
+		l.pos++
+		return token{kind: tokenRParen, pos: start}, nil
+	case r == '|':
+		l.pos++
+		return token{kind: tokenOr, pos: start}, nil
+	case r == '&':
+		l.pos++
+		return token{kind: tokenAnd, pos: start}, nil
+	case r == '-' && l.pos+1 < len(l.input) && !unicode.IsSpace(l.input[l.pos+1]):
+		l.pos++
+		return token{kind: tokenNot, pos: start}, nil
+	}
+
+	t := token{kind: tokenTerm, pos: start}
+
+	// a field prefix is only recognised for known fields, so text with a
+	// colon in it is still searchable
+	if name, ok := l.field(); ok {
+		t.field = name
+	}
+
+	value, quoted, regex, err := l.value()
+	if err != nil {
+		return t, err
+	}
+	t.value, t.quoted, t.regex = value, quoted, regex
+
+	if t.field == "" && !quoted && !regex {
+		switch value {
+		case "OR":
+			return token{kind: tokenOr, pos: start}, nil
+		case "AND":
+			return token{kind: tokenAnd, pos: start}, nil
+		case "NOT":
+			return token{kind: tokenNot, pos: start}, nil
+		}
+	}
+
+	return t, nil
+}
+
+func (l *lexer) field() (string, bool) {
+	end := l.pos
+	for end < len(l.input) && unicode.IsLetter(l.input[end]) {
+		end++
+	}
+
+	if end == l.pos || end >= len(l.input) || l.input[end] != ':' {
+		return "", false
+	}
+
+	name := strings.ToLower(string(l.input[l.pos:end]))
+	if _, ok := fields[name]; !ok {
+		return "", false
+	}
+
+	l.pos = end + 1
+	return name, true
+}
+
+func (l *lexer) value() (value string, quoted bool, regex bool, err error) {
+	start := l.pos
+
+	switch r := l.peek(); r {
+	case '"', '\'', '/':
+		l.pos++
+		var b strings.Builder
+		for l.pos < len(l.input) {
+			c := l.input[l.pos]
+			if c == '\\' && l.pos+1 < len(l.input) && l.input[l.pos+1] == r {
+				b.WriteRune(r)
+				l.pos += 2
+				continue
+			}
+			if c == r {
+				l.pos++
+				if r == '/' {
+					return b.String(), false, true, nil
+				}
+				return b.String(), true, false, nil
+			}
+			b.WriteRune(c)
+			l.pos++
+		}
+
+		if r == '/' {
+			return "", false, false, errorAt(start, "unterminated regex, expected a closing /")
+		}
+		return "", false, false, errorAt(start, "unterminated quote, expected a closing %c", r)
+	}
+
+	var b strings.Builder
+	for l.pos < len(l.input) {
+		c := l.input[l.pos]
+		if c == '\\' && l.pos+1 < len(l.input) && l.input[l.pos+1] == ' ' {
+			b.WriteRune(' ')
+			l.pos += 2
+			continue
+		}
+		if unicode.IsSpace(c) || c == '(' || c == ')' {
+			break
+		}
+		b.WriteRune(c)
+		l.pos++
+	}
+
+	return b.String(), false, false, nil
+}
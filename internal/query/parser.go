@@ -0,0 +1,253 @@
+package query
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+	opts   Options
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) advance() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// or := and (OR and)*
+func (p *parser) parseOr() (node, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	nodes := or{first}
+	for p.peek().kind == tokenOr {
+		op := p.advance()
+		if !p.startsTerm() {
+			return nil, errorAt(op.pos, "expected a term after OR")
+		}
+
+		n, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, n)
+	}
+
+	if len(nodes) == 1 {
+		return first, nil
+	}
+	return nodes, nil
+}
+
+// and := unary ([AND] unary)*
+//
+// Feed and tag terms that are only next to each other, without an explicit
+// AND, match any of them, so `f:one f:two` shows both feeds.
+func (p *parser) parseAnd() (node, error) {
+	var (
+		nodes []node
+		feeds or
+		tags  or
+	)
+
+	add := func(n node, explicit bool) {
+		if t, ok := n.(fieldTerm); ok && !explicit {
+			switch t.kind {
+			case fieldFeed:
+				feeds = append(feeds, n)
+				return
+			case fieldTag:
+				tags = append(tags, n)
+				return
+			}
+		}
+		nodes = append(nodes, n)
+	}
+
+	first, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	add(first, false)
+
+	for {
+		explicit := false
+		if p.peek().kind == tokenAnd {
+			op := p.advance()
+			if !p.startsTerm() {
+				return nil, errorAt(op.pos, "expected a term after AND")
+			}
+			explicit = true
+		} else if !p.startsTerm() {
+			break
+		}
+
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		add(n, explicit)
+	}
+
+	for _, group := range []or{feeds, tags} {
+		switch len(group) {
+		case 0:
+		case 1:
+			nodes = append(nodes, group[0])
+		default:
+			nodes = append(nodes, group)
+		}
+	}
+
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return and(nodes), nil
+}
+
+func (p *parser) startsTerm() bool {
+	switch p.peek().kind {
+	case tokenTerm, tokenLParen, tokenNot:
+		return true
+	}
+	return false
+}
+
+// unary := NOT unary | primary
+func (p *parser) parseUnary() (node, error) {
+	if p.peek().kind == tokenNot {
+		op := p.advance()
+		if !p.startsTerm() {
+			return nil, errorAt(op.pos, "expected a term after NOT")
+		}
+
+		n, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return not{n}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+// primary := '(' or ')' | term
+func (p *parser) parsePrimary() (node, error) {
+	t := p.advance()
+
+	switch t.kind {
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, errorAt(t.pos, "empty group")
+		}
+
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek().kind != tokenRParen {
+			return nil, errorAt(t.pos, "missing ) to close this (")
+		}
+		p.advance()
+
+		return n, nil
+
+	case tokenTerm:
+		return p.term(t)
+	}
+
+	return nil, errorAt(t.pos, "unexpected %s", t.kind)
+}
+
+func (p *parser) term(t token) (node, error) {
+	if t.field == "" {
+		m, err := p.matcher(t, contains)
+		if err != nil {
+			return nil, err
+		}
+		return textTerm{m: m, includeFeedName: p.opts.IncludeFeedName}, nil
+	}
+
+	if t.value == "" && !t.quoted && !t.regex {
+		return nil, errorAt(t.pos, "%s: needs a value", t.field)
+	}
+
+	kind := fields[t.field]
+	switch kind {
+	case fieldIs:
+		return p.is(t)
+	case fieldBefore, fieldAfter:
+		return p.date(t, kind)
+	case fieldFeed:
+		m, err := p.matcher(t, fuzzily)
+		if err != nil {
+			return nil, err
+		}
+		return fieldTerm{kind: kind, m: m}, nil
+	case fieldTag:
+		m, err := p.matcher(t, equals)
+		if err != nil {
+			return nil, err
+		}
+		return fieldTerm{kind: kind, m: m}, nil
+	}
+
+	m, err := p.matcher(t, contains)
+	if err != nil {
+		return nil, err
+	}
+	return fieldTerm{kind: kind, m: m}, nil
+}
+
+// matcher builds a regex matcher for /regex/ values and uses def otherwise
+func (p *parser) matcher(t token, def func(string) matcher) (matcher, error) {
+	if !t.regex {
+		return def(t.value), nil
+	}
+
+	re, err := regexp.Compile("(?i)" + t.value)
+	if err != nil {
+		return nil, errorAt(t.pos, "invalid regex: %s", strings.TrimPrefix(err.Error(), "error parsing regexp: "))
+	}
+
+	return matches(re), nil
+}
+
+func (p *parser) is(t token) (node, error) {
+	switch strings.ToLower(t.value) {
+	case "unread":
+		return predicate(func(it Item) bool { return !it.Read }), nil
+	case "read":
+		return predicate(func(it Item) bool { return it.Read }), nil
+	case "fav", "favourite", "favorite":
+		return predicate(func(it Item) bool { return it.Favourite }), nil
+	}
+
+	return nil, errorAt(t.pos, "is: expected unread, read or fav, got %q", t.value)
+}
+
+func (p *parser) date(t token, kind fieldKind) (node, error) {
+	at, err := config.ParseViewTime(t.value, p.opts.Now)
+	if err != nil {
+		return nil, errorAt(t.pos, "%s: %s, expected a date like 2006-01-02 or an age like 7d", t.field, err)
+	}
+
+	if kind == fieldBefore {
+		return predicate(func(it Item) bool { return !it.Published.IsZero() && it.Published.Before(at) }), nil
+	}
+	return predicate(func(it Item) bool { return !it.Published.IsZero() && !it.Published.Before(at) }), nil
+}
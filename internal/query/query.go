@@ -0,0 +1,247 @@
+// Package query parses and evaluates the filter language used in the list.
+//
+// A query is made of terms, which are plain text matched against the title
+// or `field:value` pairs. Values can be quoted, or written as `/regex/`.
+// Terms are combined with AND (the default when terms are next to each
+// other), OR and NOT, or their shorthands `&`, `|` and `-`, and grouped with
+// parentheses. NOT binds tightest, then AND, then OR.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sahilm/fuzzy"
+)
+
+// Item is what a query is evaluated against
+type Item struct {
+	Title     string
+	FeedName  string
+	Author    string
+	Content   string
+	Tags      []string
+	Read      bool
+	Favourite bool
+	Published time.Time
+}
+
+type Options struct {
+	// Now is used for relative dates, defaulting to the current time
+	Now time.Time
+	// IncludeFeedName matches plain text against the feed name as well as
+	// the title
+	IncludeFeedName bool
+}
+
+// Error is a problem with a query, at a rune offset into it
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg)
+}
+
+func errorAt(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type fieldKind int
+
+const (
+	fieldTitle fieldKind = iota
+	fieldFeed
+	fieldAuthor
+	fieldTag
+	fieldContent
+	fieldIs
+	fieldBefore
+	fieldAfter
+)
+
+var fields = map[string]fieldKind{
+	"title":    fieldTitle,
+	"feedname": fieldFeed,
+	"feed":     fieldFeed,
+	"f":        fieldFeed,
+	"author":   fieldAuthor,
+	"tag":      fieldTag,
+	"category": fieldTag,
+	"content":  fieldContent,
+	"is":       fieldIs,
+	"before":   fieldBefore,
+	"after":    fieldAfter,
+}
+
+// Query is a parsed query, safe to match from multiple goroutines
+type Query struct {
+	root node
+	text string
+}
+
+// Parse parses a query. An empty query matches everything.
+func Parse(input string, opts Options) (*Query, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens, opts: opts}
+
+	q := &Query{}
+	if p.peek().kind == tokenEOF {
+		q.root = all{}
+		return q, nil
+	}
+
+	q.root, err = p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != tokenEOF {
+		if t.kind == tokenRParen {
+			return nil, errorAt(t.pos, "unexpected ), no matching (")
+		}
+		return nil, errorAt(t.pos, "unexpected %s", t.kind)
+	}
+
+	if isText(tokens) {
+		q.text = strings.TrimSpace(input)
+	}
+
+	return q, nil
+}
+
+func (q *Query) Match(it Item) bool {
+	return q.root.match(it)
+}
+
+// Text returns the query when it is only plain words, which callers may
+// prefer to rank fuzzily instead of matching exactly.
+func (q *Query) Text() (string, bool) {
+	return q.text, q.text != ""
+}
+
+type node interface {
+	match(it Item) bool
+}
+
+type all struct{}
+
+func (all) match(Item) bool { return true }
+
+type and []node
+
+func (a and) match(it Item) bool {
+	for _, n := range a {
+		if !n.match(it) {
+			return false
+		}
+	}
+	return true
+}
+
+type or []node
+
+func (o or) match(it Item) bool {
+	for _, n := range o {
+		if n.match(it) {
+			return true
+		}
+	}
+	return false
+}
+
+type not struct {
+	n node
+}
+
+func (n not) match(it Item) bool {
+	return !n.n.match(it)
+}
+
+// matcher matches a string value, either by substring, equality, fuzzily
+// or by regex
+type matcher func(s string) bool
+
+type textTerm struct {
+	m               matcher
+	includeFeedName bool
+}
+
+func (t textTerm) match(it Item) bool {
+	return t.m(it.Title) || (t.includeFeedName && t.m(it.FeedName))
+}
+
+type fieldTerm struct {
+	kind fieldKind
+	m    matcher
+}
+
+func (t fieldTerm) match(it Item) bool {
+	switch t.kind {
+	case fieldTitle:
+		return t.m(it.Title)
+	case fieldFeed:
+		return t.m(it.FeedName)
+	case fieldAuthor:
+		return t.m(it.Author)
+	case fieldContent:
+		return t.m(it.Content)
+	case fieldTag:
+		return slices.ContainsFunc(it.Tags, func(tag string) bool { return t.m(tag) })
+	}
+	return false
+}
+
+type predicate func(it Item) bool
+
+func (p predicate) match(it Item) bool { return p(it) }
+
+// isText reports whether the query is just plain words
+func isText(tokens []token) bool {
+	for _, t := range tokens {
+		if t.kind == tokenEOF {
+			continue
+		}
+		if t.kind != tokenTerm || t.field != "" || t.quoted || t.regex {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(value string) matcher {
+	value = strings.ToLower(value)
+	return func(s string) bool {
+		return strings.Contains(strings.ToLower(s), value)
+	}
+}
+
+func equals(value string) matcher {
+	return func(s string) bool {
+		return strings.EqualFold(s, value)
+	}
+}
+
+// fuzzily matches the characters of value in order, as feed filters always
+// have
+func fuzzily(value string) matcher {
+	return func(s string) bool {
+		return len(fuzzy.Find(value, []string{s})) > 0
+	}
+}
+
+func matches(re *regexp.Regexp) matcher {
+	return re.MatchString
+}
@@ -0,0 +1,183 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+var now = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+var items = []Item{
+	{Title: "Go 1.22 released", FeedName: "golang", Author: "The Go Team", Tags: []string{"go", "releases"}, Published: now.AddDate(0, 0, -1)},
+	{Title: "Rust 1.75 released", FeedName: "rust", Author: "The Rust Team", Tags: []string{"releases"}, Read: true, Published: now.AddDate(0, 0, -20)},
+	{Title: "Why I write Go", FeedName: "blog", Author: "Alice", Content: "<p>Generics finally landed</p>", Favourite: true, Published: now.AddDate(0, 0, -3)},
+	{Title: "Weekly links", FeedName: "my feed - with spaces", Author: "Bob", Published: now.AddDate(-1, 0, 0)},
+}
+
+func matching(t *testing.T, q string) string {
+	t.Helper()
+
+	parsed, err := Parse(q, Options{Now: now})
+	test.HandleError(t, err)
+
+	var titles []string
+	for _, it := range items {
+		if parsed.Match(it) {
+			titles = append(titles, it.Title)
+		}
+	}
+
+	return strings.Join(titles, ", ")
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"", "Go 1.22 released, Rust 1.75 released, Why I write Go, Weekly links"},
+		{"released", "Go 1.22 released, Rust 1.75 released"},
+		{"title:go", "Go 1.22 released, Why I write Go"},
+		{"f:golang", "Go 1.22 released"},
+		{"f:gl", "Go 1.22 released"},
+		{"f:golang f:rust", "Go 1.22 released, Rust 1.75 released"},
+		{"f:golang AND f:rust", ""},
+		{`feedname:"my feed - with spaces"`, "Weekly links"},
+		{`feed:my\ feed\ -\ with\ spaces`, "Weekly links"},
+		{"author:alice", "Why I write Go"},
+		{"tag:releases", "Go 1.22 released, Rust 1.75 released"},
+		{"category:Go", "Go 1.22 released"},
+		{"tag:go tag:releases", "Go 1.22 released, Rust 1.75 released"},
+		{"tag:go AND tag:releases", "Go 1.22 released"},
+		{"is:unread", "Go 1.22 released, Why I write Go, Weekly links"},
+		{"is:read", "Rust 1.75 released"},
+		{"is:fav", "Why I write Go"},
+		{"after:7d", "Go 1.22 released, Why I write Go"},
+		{"before:2024-01-01", "Weekly links"},
+		{"content:generics", "Why I write Go"},
+		{"/^go /", "Go 1.22 released"},
+		{`/\d+\.\d+/`, "Go 1.22 released, Rust 1.75 released"},
+		{"author:/team$/", "Go 1.22 released, Rust 1.75 released"},
+		{"-released", "Why I write Go, Weekly links"},
+		{"NOT is:unread", "Rust 1.75 released"},
+		{"released -tag:go", "Rust 1.75 released"},
+		{"f:rust OR is:fav", "Rust 1.75 released, Why I write Go"},
+		{"f:rust | author:bob", "Rust 1.75 released, Weekly links"},
+		{"(f:rust OR f:blog) is:unread", "Why I write Go"},
+		{"-(tag:releases | is:fav)", "Weekly links"},
+		{"go & is:unread after:2d", "Go 1.22 released"},
+		{"Go: or", ""},
+		{"'Why I'", "Why I write Go"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			test.Equal(t, c.want, matching(t, c.query), "wrong matches for "+c.query)
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	for q, want := range map[string]bool{
+		"go released":  true,
+		"  go ":        true,
+		"f:go":         false,
+		"go OR rust":   false,
+		"go -released": false,
+		`"go"`:         false,
+		"/go/":         false,
+	} {
+		parsed, err := Parse(q, Options{Now: now})
+		test.HandleError(t, err)
+
+		_, ok := parsed.Text()
+		test.Equal(t, want, ok, "wrong plain text detection for "+q)
+	}
+}
+
+func TestIncludeFeedName(t *testing.T) {
+	parsed, err := Parse("rust", Options{Now: now, IncludeFeedName: true})
+	test.HandleError(t, err)
+
+	test.Equal(t, true, parsed.Match(Item{Title: "1.75 released", FeedName: "rust"}), "feed name should match plain text")
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{`tag:"releases`, 4, "unterminated quote"},
+		{"/go", 0, "unterminated regex"},
+		{"/(go/", 0, "invalid regex"},
+		{"(go OR rust", 0, "missing )"},
+		{"go)", 2, "unexpected )"},
+		{"()", 0, "empty group"},
+		{"go OR", 3, "expected a term after OR"},
+		{"go AND", 3, "expected a term after AND"},
+		{"go NOT", 3, "expected a term after NOT"},
+		{"tag:", 0, "needs a value"},
+		{"is:everything", 0, "expected unread, read or fav"},
+		{"after:someday", 0, "after:"},
+		{"OR go", 0, "unexpected OR"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			_, err := Parse(c.query, Options{Now: now})
+
+			var qe *Error
+			if !errors.As(err, &qe) {
+				t.Fatalf("expected a query error, got %v", err)
+			}
+
+			test.Equal(t, c.pos, qe.Pos, "wrong error position")
+			if !strings.Contains(qe.Msg, c.msg) {
+				t.Fatalf("expected error to contain %q, got %q", c.msg, qe.Msg)
+			}
+		})
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"go released",
+		`f:golang f:"my feed" -tag:releases`,
+		"(is:unread OR is:fav) AND after:7d",
+		`/\d+/ content:'a \' quote' -(a | b)`,
+		"before:2024-01-01 author:/^a/ NOT title:x",
+		"((((",
+		`feed:my\ feed`,
+		"- -- ---",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		q, err := Parse(input, Options{Now: now})
+		if err != nil {
+			var qe *Error
+			if !errors.As(err, &qe) {
+				t.Fatalf("unexpected error type %T: %v", err, err)
+			}
+			if qe.Pos < 0 || qe.Pos > len([]rune(input)) {
+				t.Fatalf("error position %d outside of %q", qe.Pos, input)
+			}
+			return
+		}
+
+		for _, it := range items {
+			q.Match(it)
+		}
+
+		if text, ok := q.Text(); ok && text != strings.TrimSpace(input) {
+			t.Fatalf("plain text %q should be the trimmed input %q", text, input)
+		}
+	})
+}
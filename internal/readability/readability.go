@@ -0,0 +1,262 @@
+// Package readability extracts the main article from a web page, dropping
+// navigation, comments and other boilerplate. It follows the scoring
+// approach of Arc90's readability: paragraphs award points to their parent
+// and grandparent elements, and the best scoring element, along with any
+// siblings that look like part of the same article, is kept.
+package readability
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+var ErrNoContent = errors.New("readability: no content found")
+
+const (
+	// paragraphs shorter than this don't count towards a candidate
+	minParagraphLength = 25
+	// siblings need at least this score to be kept with the top candidate
+	minSiblingScore = 10
+)
+
+var (
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumbs|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|share|newsletter|subscribe|cookie`)
+	maybeCandidate     = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	positiveNames      = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeNames      = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// elements that are never part of the article
+const noise = "script, style, noscript, iframe, form, nav, aside, button, input, select, textarea, svg, link, meta"
+
+type Article struct {
+	Title   string
+	Content string
+}
+
+// Extract returns the main content of the page read from r as HTML. Links
+// and images are resolved against base when it is given.
+func Extract(r io.Reader, base *url.URL) (Article, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Article{}, err
+	}
+
+	article := Article{
+		Title: strings.TrimSpace(doc.Find("title").First().Text()),
+	}
+
+	doc.Find(noise).Remove()
+	removeUnlikely(doc)
+
+	if base != nil {
+		resolveURLs(doc, base)
+	}
+
+	content := topCandidate(doc)
+	if content == nil {
+		content = fallback(doc)
+	}
+	if content == nil {
+		return article, ErrNoContent
+	}
+
+	article.Content, err = content.Html()
+	if err != nil {
+		return article, err
+	}
+
+	return article, nil
+}
+
+func removeUnlikely(doc *goquery.Document) {
+	doc.Find("body *").Each(func(_ int, s *goquery.Selection) {
+		if s.Is("body, article, main, a") {
+			return
+		}
+
+		names := className(s)
+		if unlikelyCandidates.MatchString(names) && !maybeCandidate.MatchString(names) {
+			s.Remove()
+		}
+	})
+}
+
+func resolveURLs(doc *goquery.Document, base *url.URL) {
+	for _, attr := range []string{"href", "src"} {
+		doc.Find("[" + attr + "]").Each(func(_ int, s *goquery.Selection) {
+			v, _ := s.Attr(attr)
+			ref, err := url.Parse(v)
+			if err != nil {
+				return
+			}
+			s.SetAttr(attr, base.ResolveReference(ref).String())
+		})
+	}
+}
+
+// topCandidate scores the parents of every paragraph and returns the best
+// one, wrapped together with related siblings.
+func topCandidate(doc *goquery.Document) *goquery.Selection {
+	scores := map[*html.Node]float64{}
+
+	doc.Find("p, pre, td, blockquote").Each(func(_ int, s *goquery.Selection) {
+		text := strings.TrimSpace(s.Text())
+		if len(text) < minParagraphLength {
+			return
+		}
+
+		score := 1 + float64(strings.Count(text, ",")) + math.Min(float64(len(text))/100, 3)
+
+		parent := s.Parent()
+		if parent.Length() == 0 {
+			return
+		}
+		addScore(scores, parent, score)
+
+		grandparent := parent.Parent()
+		if grandparent.Length() > 0 && !grandparent.Is("html") {
+			addScore(scores, grandparent, score/2)
+		}
+	})
+
+	var (
+		best      *html.Node
+		bestScore float64
+	)
+	for n, score := range scores {
+		score *= 1 - linkDensity(goquery.NewDocumentFromNode(n).Selection)
+		scores[n] = score
+		if best == nil || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	top := goquery.NewDocumentFromNode(best).Selection
+	if top.Is("body") {
+		return top
+	}
+
+	threshold := math.Max(minSiblingScore, bestScore*0.2)
+	wrapper := goquery.NewDocumentFromNode(&html.Node{Type: html.ElementNode, Data: "div"}).Selection
+	top.Parent().Children().Each(func(_ int, s *goquery.Selection) {
+		if keepSibling(s, best, scores, threshold) {
+			wrapper.AppendSelection(s.Clone())
+		}
+	})
+
+	return wrapper
+}
+
+func keepSibling(s *goquery.Selection, best *html.Node, scores map[*html.Node]float64, threshold float64) bool {
+	n := s.Get(0)
+	if n == best {
+		return true
+	}
+
+	if scores[n] >= threshold {
+		return true
+	}
+
+	if s.Is("p") {
+		text := strings.TrimSpace(s.Text())
+		density := linkDensity(s)
+		if len(text) > 80 && density < 0.25 {
+			return true
+		}
+		if len(text) > 0 && density == 0 && strings.HasSuffix(text, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func addScore(scores map[*html.Node]float64, s *goquery.Selection, score float64) {
+	n := s.Get(0)
+	if _, ok := scores[n]; !ok {
+		scores[n] = initialScore(s)
+	}
+	scores[n] += score
+}
+
+func initialScore(s *goquery.Selection) float64 {
+	score := classWeight(s)
+
+	switch goquery.NodeName(s) {
+	case "div", "article":
+		score += 5
+	case "pre", "td", "blockquote":
+		score += 3
+	case "address", "ol", "ul", "dl", "dd", "dt", "li", "form":
+		score -= 3
+	case "h1", "h2", "h3", "h4", "h5", "h6", "th":
+		score -= 5
+	}
+
+	return score
+}
+
+func classWeight(s *goquery.Selection) float64 {
+	var weight float64
+
+	for _, attr := range []string{"class", "id"} {
+		v, ok := s.Attr(attr)
+		if !ok || v == "" {
+			continue
+		}
+		if negativeNames.MatchString(v) {
+			weight -= 25
+		}
+		if positiveNames.MatchString(v) {
+			weight += 25
+		}
+	}
+
+	return weight
+}
+
+// linkDensity is the share of an element's text that is inside links
+func linkDensity(s *goquery.Selection) float64 {
+	textLength := len(strings.TrimSpace(s.Text()))
+	if textLength == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	s.Find("a").Each(func(_ int, a *goquery.Selection) {
+		linkLength += len(strings.TrimSpace(a.Text()))
+	})
+
+	return float64(linkLength) / float64(textLength)
+}
+
+func className(s *goquery.Selection) string {
+	class, _ := s.Attr("class")
+	id, _ := s.Attr("id")
+	return class + " " + id
+}
+
+// fallback is used for pages without enough prose to score, preferring
+// the most specific content container.
+func fallback(doc *goquery.Document) *goquery.Selection {
+	for _, sel := range []string{"article", "main", "[role=main]", "body"} {
+		s := doc.Find(sel).First()
+		if s.Length() > 0 {
+			return s
+		}
+	}
+
+	return nil
+}
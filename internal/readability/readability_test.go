@@ -0,0 +1,48 @@
+package readability
+
+import (
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+const fixture = "../test/data/readability_fixture.html"
+
+func TestExtract(t *testing.T) {
+	f, err := os.Open(fixture)
+	test.HandleError(t, err)
+	defer f.Close()
+
+	base, _ := url.Parse("https://acme.example/blog/sqlite")
+	article, err := Extract(f, base)
+	test.HandleError(t, err)
+
+	test.Equal(t, "Why we moved to SQLite | Acme Engineering", article.Title, "bad title")
+
+	for _, want := range []string{
+		"For years we ran a dedicated database cluster",
+		"the right choice for us.",
+		`href="https://acme.example/blog/benchmarks"`,
+		`src="https://acme.example/blog/images/latency.png"`,
+	} {
+		if !strings.Contains(article.Content, want) {
+			t.Errorf("expected content to contain %q, got %s", want, article.Content)
+		}
+	}
+
+	for _, unwanted := range []string{"Popular posts", "Great post", "Copyright", "tracking", "Jobs"} {
+		if strings.Contains(article.Content, unwanted) {
+			t.Errorf("expected %q to be removed, got %s", unwanted, article.Content)
+		}
+	}
+}
+
+func TestExtractShortPage(t *testing.T) {
+	article, err := Extract(strings.NewReader(`<html><body><div class="header">Site</div><main><p>Just a page.</p></main></body></html>`), nil)
+	test.HandleError(t, err)
+
+	test.Equal(t, "<p>Just a page.</p>", strings.TrimSpace(article.Content), "short pages should fall back to the main element")
+}
@@ -0,0 +1,95 @@
+package rss
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/readability"
+)
+
+// images bigger than this are cut short, and so fail to decode
+const maxImageSize = 20 << 20
+
+// FetchArticle fetches the page at link and returns the HTML of its main
+// content, for reading items whose feeds only carry a summary.
+func FetchArticle(link string, httpOpts *config.HTTPOptions, version string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return "", fmt.Errorf("rss.FetchArticle: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent(version))
+
+	resp, err := newHTTPClient(httpOpts).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("rss.FetchArticle: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("rss.FetchArticle: %s returned status %d", link, resp.StatusCode)
+	}
+
+	base, _ := url.Parse(link)
+	article, err := readability.Extract(resp.Body, base)
+	if err != nil {
+		return "", fmt.Errorf("rss.FetchArticle: %w", err)
+	}
+
+	return article.Content, nil
+}
+
+// FetchImage fetches an image in an article, to show it inline.
+func FetchImage(link string, httpOpts *config.HTTPOptions, version string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("rss.FetchImage: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent(version))
+
+	resp, err := newHTTPClient(httpOpts).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("rss.FetchImage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("rss.FetchImage: %s returned status %d", link, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageSize))
+	if err != nil {
+		return nil, fmt.Errorf("rss.FetchImage: %w", err)
+	}
+
+	return data, nil
+}
+
+// FetchEnclosure starts downloading a podcast enclosure, returning its body
+// and length, -1 if unknown. Only waiting for the response is timed, as
+// episodes can take a while to download.
+func FetchEnclosure(link string, httpOpts *config.HTTPOptions, version string) (io.ReadCloser, int64, error) {
+	req, err := http.NewRequest(http.MethodGet, link, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("rss.FetchEnclosure: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent(version))
+
+	client := newHTTPClient(httpOpts)
+	client.Timeout = 0
+	client.Transport.(*http.Transport).ResponseHeaderTimeout = requestTimeout
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("rss.FetchEnclosure: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		resp.Body.Close()
+		return nil, 0, fmt.Errorf("rss.FetchEnclosure: %s returned status %d", link, resp.StatusCode)
+	}
+
+	return resp.Body, resp.ContentLength, nil
+}
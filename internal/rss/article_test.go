@@ -0,0 +1,27 @@
+package rss
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestFetchArticle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body>
+<nav>Home | About</nav>
+<article><h1>Offline</h1><script>track()</script><p>Full text.</p></article>
+<footer>(c) me</footer>
+</body></html>`)
+	}))
+	defer srv.Close()
+
+	content, err := FetchArticle(srv.URL, nil, "test")
+	test.HandleError(t, err)
+
+	test.Equal(t, "<h1>Offline</h1><p>Full text.</p>", strings.TrimSpace(content), "bad article content")
+}
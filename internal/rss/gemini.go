@@ -0,0 +1,407 @@
+package rss
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+const (
+	geminiKnownHostsFileName = "gemini_known_hosts"
+	geminiDefaultPort        = "1965"
+	geminiMaxRedirects       = 5
+	geminiMaxResponseSize    = 16 * 1024 * 1024
+	geminiTimeout            = 30 * time.Second
+)
+
+var (
+	ErrGeminiCertificateChanged = errors.New("gemini certificate does not match the pinned certificate")
+
+	// gemfeed entries are link lines whose text starts with an ISO date
+	gemfeedEntry = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})\s*(?:[-–—:]\s*)?(.*)$`)
+
+	// known hosts is shared by concurrent fetches
+	knownHostsMu sync.Mutex
+)
+
+func IsGemini(rawURL string) bool {
+	return strings.HasPrefix(strings.ToLower(rawURL), "gemini://")
+}
+
+type geminiResponse struct {
+	status int
+	meta   string
+	body   []byte
+	url    string
+}
+
+func (r geminiResponse) mediaType() string {
+	mt, _, err := mime.ParseMediaType(r.meta)
+	if err != nil {
+		return r.meta
+	}
+	return mt
+}
+
+// geminiClient fetches gemini:// URLs, pinning the certificate of each host
+// the first time it is seen (TOFU) in a known hosts file.
+type geminiClient struct {
+	knownHostsPath string
+}
+
+func newGeminiClient(configDir string) *geminiClient {
+	return &geminiClient{
+		knownHostsPath: filepath.Join(configDir, geminiKnownHostsFileName),
+	}
+}
+
+func (c *geminiClient) get(rawURL string) (geminiResponse, error) {
+	for i := 0; i <= geminiMaxRedirects; i++ {
+		resp, err := c.request(rawURL)
+		if err != nil {
+			return resp, err
+		}
+
+		switch resp.status / 10 {
+		case 2:
+			return resp, nil
+		case 3:
+			base, _ := url.Parse(rawURL)
+			next, err := url.Parse(resp.meta)
+			if err != nil {
+				return resp, fmt.Errorf("gemini: bad redirect from %s: %w", rawURL, err)
+			}
+			rawURL = base.ResolveReference(next).String()
+		default:
+			return resp, fmt.Errorf("gemini: %s returned %d %s", rawURL, resp.status, resp.meta)
+		}
+	}
+
+	return geminiResponse{}, fmt.Errorf("gemini: too many redirects fetching %s", rawURL)
+}
+
+func (c *geminiClient) request(rawURL string) (geminiResponse, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return geminiResponse{}, fmt.Errorf("gemini: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), geminiDefaultPort)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: geminiTimeout}, "tcp", host, &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: u.Hostname(),
+		// gemini servers mostly use self-signed certificates, trust is
+		// established by pinning instead
+		InsecureSkipVerify: true,
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return fmt.Errorf("gemini: %s sent no certificate", host)
+			}
+			return c.verify(host, cs.PeerCertificates[0].Raw)
+		},
+	})
+	if err != nil {
+		return geminiResponse{}, fmt.Errorf("gemini: %w", err)
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(geminiTimeout))
+
+	if _, err := fmt.Fprintf(conn, "%s\r\n", u.String()); err != nil {
+		return geminiResponse{}, fmt.Errorf("gemini: %w", err)
+	}
+
+	r := bufio.NewReader(conn)
+	header, err := r.ReadString('\n')
+	if err != nil {
+		return geminiResponse{}, fmt.Errorf("gemini: reading header: %w", err)
+	}
+
+	header = strings.TrimRight(header, "\r\n")
+	code, meta, _ := strings.Cut(header, " ")
+	status, err := strconv.Atoi(code)
+	if err != nil || len(code) != 2 {
+		return geminiResponse{}, fmt.Errorf("gemini: malformed header %q", header)
+	}
+
+	resp := geminiResponse{status: status, meta: strings.TrimSpace(meta), url: u.String()}
+	if status/10 != 2 {
+		return resp, nil
+	}
+
+	resp.body, err = io.ReadAll(io.LimitReader(r, geminiMaxResponseSize))
+	if err != nil {
+		return resp, fmt.Errorf("gemini: reading body: %w", err)
+	}
+
+	return resp, nil
+}
+
+// verify checks a certificate against the pinned fingerprint for host,
+// pinning it if the host hasn't been seen before.
+func (c *geminiClient) verify(host string, cert []byte) error {
+	sum := sha256.Sum256(cert)
+	fingerprint := hex.EncodeToString(sum[:])
+
+	knownHostsMu.Lock()
+	defer knownHostsMu.Unlock()
+
+	hosts, err := readKnownHosts(c.knownHostsPath)
+	if err != nil {
+		return err
+	}
+
+	if pinned, ok := hosts[host]; ok {
+		if pinned != fingerprint {
+			return fmt.Errorf("%w for %s, remove it from %s if the change is expected", ErrGeminiCertificateChanged, host, c.knownHostsPath)
+		}
+		return nil
+	}
+
+	f, err := os.OpenFile(c.knownHostsPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("gemini: pinning certificate: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s %s\n", host, fingerprint); err != nil {
+		return fmt.Errorf("gemini: pinning certificate: %w", err)
+	}
+
+	return nil
+}
+
+func readKnownHosts(path string) (map[string]string, error) {
+	hosts := map[string]string{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return hosts, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gemini: reading known hosts: %w", err)
+	}
+
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			hosts[fields[0]] = fields[1]
+		}
+	}
+
+	return hosts, nil
+}
+
+// fetchGemini fetches either an Atom/RSS feed served over gemini or a
+// gemfeed-style gemtext index. The gemtext of each entry is fetched later,
+// with FetchGemtext, once it is known which entries are new.
+func fetchGemini(f config.Feed, configDir string) (RSS, error) {
+	resp, err := newGeminiClient(configDir).get(f.URL)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchGemini: %w", err)
+	}
+
+	if resp.mediaType() == "text/gemini" {
+		return gemfeedToRSS(f, resp.url, string(resp.body)), nil
+	}
+
+	feed, err := gofeed.NewParser().Parse(bytes.NewReader(resp.body))
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchGemini: %w", err)
+	}
+
+	return feedToRSS(f, feed), nil
+}
+
+// FetchGemtext fetches the gemtext a feed entry links to, failing for
+// anything served as another media type
+func FetchGemtext(link string, configDir string) (string, error) {
+	resp, err := newGeminiClient(configDir).get(link)
+	if err != nil {
+		return "", fmt.Errorf("rss.FetchGemtext: %w", err)
+	}
+
+	if mt := resp.mediaType(); mt != "text/gemini" {
+		return "", fmt.Errorf("rss.FetchGemtext: %s is %s, not text/gemini", link, mt)
+	}
+
+	return string(resp.body), nil
+}
+
+// gemfeedToRSS parses a gemtext page following the gemfeed convention: the
+// first level one heading is the feed title and every link line whose text
+// starts with a YYYY-MM-DD date is an entry.
+func gemfeedToRSS(f config.Feed, feedURL string, body string) RSS {
+	base, _ := url.Parse(feedURL)
+	items := make([]Item, 0)
+	title := ""
+	pre := false
+
+	for _, line := range strings.Split(body, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasPrefix(line, "```") {
+			pre = !pre
+			continue
+		}
+		if pre {
+			continue
+		}
+
+		if title == "" && strings.HasPrefix(line, "# ") {
+			title = strings.TrimSpace(line[2:])
+			continue
+		}
+
+		link, text, ok := parseGemtextLink(line)
+		if !ok {
+			continue
+		}
+
+		m := gemfeedEntry.FindStringSubmatch(text)
+		if m == nil {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", m[1])
+		if err != nil {
+			continue
+		}
+
+		ref, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+
+		entryTitle := strings.TrimSpace(m[2])
+		if entryTitle == "" {
+			entryTitle = m[1]
+		}
+
+		items = append(items, Item{
+			Title:    entryTitle,
+			Link:     base.ResolveReference(ref).String(),
+			PubDate:  date,
+			FeedName: f.Name,
+		})
+	}
+
+	rss := RSS{}
+	rss.Channel = Channel{
+		Title: title,
+		Link:  feedURL,
+		Items: items,
+	}
+
+	return rss
+}
+
+func parseGemtextLink(line string) (link string, text string, ok bool) {
+	if !strings.HasPrefix(line, "=>") {
+		return "", "", false
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(line, "=>"))
+	if len(fields) == 0 {
+		return "", "", false
+	}
+
+	link = fields[0]
+	text = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(strings.TrimPrefix(line, "=>")), link))
+
+	return link, text, true
+}
+
+// GemtextToMarkdown converts text/gemini into markdown for glamour. Gemtext
+// is line oriented, so every text line becomes its own paragraph and links
+// are resolved against base.
+func GemtextToMarkdown(gemtext string, base string) string {
+	baseURL, _ := url.Parse(base)
+
+	var out strings.Builder
+	pre := false
+	inList := false
+
+	for _, line := range strings.Split(gemtext, "\n") {
+		line = strings.TrimRight(line, "\r")
+
+		if strings.HasPrefix(line, "```") {
+			if inList {
+				out.WriteString("\n")
+				inList = false
+			}
+			pre = !pre
+			out.WriteString("```\n")
+			if !pre {
+				out.WriteString("\n")
+			}
+			continue
+		}
+
+		if pre {
+			out.WriteString(line + "\n")
+			continue
+		}
+
+		if strings.HasPrefix(line, "* ") {
+			out.WriteString("- " + strings.TrimSpace(line[2:]) + "\n")
+			inList = true
+			continue
+		}
+
+		if inList {
+			out.WriteString("\n")
+			inList = false
+		}
+
+		switch {
+		case strings.HasPrefix(line, "=>"):
+			link, text, ok := parseGemtextLink(line)
+			if !ok {
+				continue
+			}
+			if ref, err := url.Parse(link); err == nil && baseURL != nil {
+				link = baseURL.ResolveReference(ref).String()
+			}
+			if text == "" {
+				text = link
+			}
+			out.WriteString(fmt.Sprintf("→ [%s](%s)\n\n", text, link))
+		case strings.HasPrefix(line, "#"), strings.HasPrefix(line, ">"):
+			out.WriteString(line + "\n\n")
+		case strings.TrimSpace(line) == "":
+			continue
+		default:
+			out.WriteString(line + "\n\n")
+		}
+	}
+
+	if pre {
+		out.WriteString("```\n")
+	}
+
+	return out.String()
+}
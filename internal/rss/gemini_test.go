@@ -0,0 +1,219 @@
+package rss
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+type geminiStub struct {
+	status int
+	meta   string
+	body   string
+}
+
+// serveGemini starts a local stand-in for a gemini server answering each path
+// with a fixed response, and returns its address.
+func serveGemini(t *testing.T, routes map[string]geminiStub) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	test.HandleError(t, err)
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	test.HandleError(t, err)
+
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	})
+	test.HandleError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+
+			go func(conn net.Conn) {
+				defer conn.Close()
+
+				line, err := bufio.NewReader(conn).ReadString('\n')
+				if err != nil {
+					return
+				}
+
+				u, err := url.Parse(strings.TrimSpace(line))
+				if err != nil {
+					fmt.Fprint(conn, "59 bad request\r\n")
+					return
+				}
+
+				stub, ok := routes[u.Path]
+				if !ok {
+					fmt.Fprint(conn, "51 not found\r\n")
+					return
+				}
+
+				fmt.Fprintf(conn, "%d %s\r\n%s", stub.status, stub.meta, stub.body)
+			}(conn)
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestFetchGemfeed(t *testing.T) {
+	addr := serveGemini(t, map[string]geminiStub{
+		"/old": {status: 31, meta: "/gemlog/"},
+		"/gemlog/": {status: 20, meta: "text/gemini; lang=en", body: strings.Join([]string{
+			"# Alice's gemlog",
+			"=> /about.gmi About me",
+			"=> 2024-02-01-tofu.gmi 2024-02-01 - Trust on first use",
+			"=> gemini://elsewhere.invalid/post.gmi 2024-01-15 Elsewhere",
+		}, "\n")},
+		"/gemlog/2024-02-01-tofu.gmi": {status: 20, meta: "text/gemini", body: "# TOFU\nPinning certificates.\n"},
+	})
+
+	dir := t.TempDir()
+	r, err := Fetch(config.Feed{URL: "gemini://" + addr + "/old", Name: "alice"}, nil, "test", dir)
+	test.HandleError(t, err)
+
+	test.Equal(t, "Alice's gemlog", r.Channel.Title, "bad channel title")
+	test.Equal(t, 2, len(r.Channel.Items), "only dated links are entries")
+
+	first := r.Channel.Items[0]
+	test.Equal(t, "Trust on first use", first.Title, "bad title")
+	test.Equal(t, "gemini://"+addr+"/gemlog/2024-02-01-tofu.gmi", first.Link, "link not resolved against redirect")
+	test.Equal(t, "2024-02-01", first.PubDate.Format("2006-01-02"), "bad date")
+	test.Equal(t, "", first.Content, "entry gemtext fetched with the feed")
+	test.Equal(t, "alice", first.FeedName, "bad feedname")
+
+	gemtext, err := FetchGemtext(first.Link, dir)
+	test.HandleError(t, err)
+	test.Equal(t, "# TOFU\nPinning certificates.\n", gemtext, "entry gemtext not fetched")
+
+	_, err = FetchGemtext("gemini://"+addr+"/about.gmi", dir)
+	if err == nil {
+		t.Fatal("expected an error for a missing entry")
+	}
+
+	hosts, err := readKnownHosts(filepath.Join(dir, geminiKnownHostsFileName))
+	test.HandleError(t, err)
+	if _, ok := hosts[addr]; !ok {
+		t.Fatalf("certificate for %s was not pinned", addr)
+	}
+}
+
+func TestFetchGeminiAtom(t *testing.T) {
+	routes := map[string]geminiStub{}
+	addr := serveGemini(t, routes)
+
+	routes["/atom.xml"] = geminiStub{status: 20, meta: "application/atom+xml", body: fmt.Sprintf(`<?xml version="1.0" encoding="utf-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Bob's capsule</title>
+  <entry>
+    <title>Hello gemini</title>
+    <link href="gemini://%s/hello.gmi"/>
+    <updated>2024-03-01T00:00:00Z</updated>
+    <content type="html">&lt;p&gt;Hi&lt;/p&gt;</content>
+  </entry>
+</feed>`, addr)}
+	routes["/hello.html"] = geminiStub{status: 20, meta: "text/html", body: "<p>Hello!</p>"}
+
+	r, err := Fetch(config.Feed{URL: "gemini://" + addr + "/atom.xml"}, nil, "test", t.TempDir())
+	test.HandleError(t, err)
+
+	test.Equal(t, "Bob's capsule", r.Channel.Title, "bad channel title")
+	test.Equal(t, 1, len(r.Channel.Items), "missing entries")
+	test.Equal(t, "Hello gemini", r.Channel.Items[0].Title, "bad title")
+	test.Equal(t, "<p>Hi</p>", r.Channel.Items[0].Content, "atom content replaced")
+
+	_, err = FetchGemtext("gemini://"+addr+"/hello.html", t.TempDir())
+	if err == nil {
+		t.Fatal("expected an error for an entry that isn't gemtext")
+	}
+}
+
+func TestGeminiPinnedCertificateMismatch(t *testing.T) {
+	addr := serveGemini(t, map[string]geminiStub{
+		"/": {status: 20, meta: "text/gemini", body: "# Empty\n"},
+	})
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, geminiKnownHostsFileName), []byte(addr+" deadbeef\n"), 0600)
+	test.HandleError(t, err)
+
+	_, err = Fetch(config.Feed{URL: "gemini://" + addr + "/"}, nil, "test", dir)
+	if !errors.Is(err, ErrGeminiCertificateChanged) {
+		t.Fatalf("expected a pinned certificate error, got %v", err)
+	}
+}
+
+func TestGeminiErrorStatus(t *testing.T) {
+	addr := serveGemini(t, map[string]geminiStub{})
+
+	_, err := Fetch(config.Feed{URL: "gemini://" + addr + "/missing"}, nil, "test", t.TempDir())
+	if err == nil || !strings.Contains(err.Error(), "51 not found") {
+		t.Fatalf("expected a not found error, got %v", err)
+	}
+}
+
+func TestGemtextToMarkdown(t *testing.T) {
+	gemtext := strings.Join([]string{
+		"# Title",
+		"Some text",
+		"* one",
+		"* two",
+		"=> other.gmi Another post",
+		"```",
+		"=> not a link",
+		"```",
+		"> quoted",
+	}, "\n")
+
+	want := strings.Join([]string{
+		"# Title",
+		"",
+		"Some text",
+		"",
+		"- one",
+		"- two",
+		"",
+		"→ [Another post](gemini://example.org/gemlog/other.gmi)",
+		"",
+		"```",
+		"=> not a link",
+		"```",
+		"",
+		"> quoted",
+		"",
+		"",
+	}, "\n")
+
+	test.Equal(t, want, GemtextToMarkdown(gemtext, "gemini://example.org/gemlog/post.gmi"), "bad markdown")
+}
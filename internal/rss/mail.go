@@ -0,0 +1,322 @@
+package rss
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+var headerDecoder = new(mime.WordDecoder)
+
+// mboxDateLayouts are the delivery dates found after the sender on mbox
+// separator lines
+var mboxDateLayouts = []string{
+	"Mon Jan _2 15:04:05 2006",
+	"Mon Jan _2 15:04:05 -0700 2006",
+	"Mon Jan _2 15:04:05 MST 2006",
+}
+
+// fetchMail reads every message in a local Maildir or mbox and turns it into
+// an item. Messages are only read, flags and locations are left untouched so
+// a mail client can share the folder.
+func fetchMail(f config.Feed) (RSS, error) {
+	path := expandHome(f.URL)
+
+	var (
+		msgs [][]byte
+		err  error
+	)
+	if f.Type == config.FeedTypeMbox {
+		msgs, err = readMbox(path)
+	} else {
+		msgs, err = readMaildir(path)
+	}
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchMail: %w", err)
+	}
+
+	items := make([]Item, 0, len(msgs))
+	for _, raw := range msgs {
+		it, err := messageToItem(raw)
+		if err != nil {
+			continue
+		}
+
+		// the feed name from config takes precedence over the sender
+		if f.Name != "" {
+			it.FeedName = f.Name
+		}
+
+		items = append(items, it)
+	}
+
+	rss := RSS{}
+	rss.Channel = Channel{
+		Title: filepath.Base(path),
+		Link:  f.URL,
+		Items: items,
+	}
+
+	return rss, nil
+}
+
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
+
+// readMaildir returns the raw messages in the new and cur directories.
+func readMaildir(path string) ([][]byte, error) {
+	var msgs [][]byte
+
+	for _, dir := range []string{"new", "cur"} {
+		entries, err := os.ReadDir(filepath.Join(path, dir))
+		if err != nil {
+			return nil, fmt.Errorf("readMaildir: %w", err)
+		}
+
+		for _, e := range entries {
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+
+			b, err := os.ReadFile(filepath.Join(path, dir, e.Name()))
+			if err != nil {
+				return nil, fmt.Errorf("readMaildir: %w", err)
+			}
+			msgs = append(msgs, b)
+		}
+	}
+
+	return msgs, nil
+}
+
+// readMbox splits an mbox file on its "From " separator lines, undoing the
+// ">From " quoting used in message bodies. Bodies aren't always quoted, so
+// only a "From " line at the start or after a blank line, with a sender and
+// date, starts a message.
+func readMbox(path string) ([][]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("readMbox: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		msgs    [][]byte
+		current *bytes.Buffer
+	)
+
+	blank := true
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		line := sc.Text()
+
+		separator := blank && isMboxSeparator(line)
+		blank = strings.TrimSpace(line) == ""
+
+		if separator {
+			if current != nil {
+				// the blank line before a separator isn't part of the message
+				msgs = append(msgs, bytes.TrimSuffix(current.Bytes(), []byte("\n")))
+			}
+			current = &bytes.Buffer{}
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+
+		if strings.HasPrefix(line, ">") && strings.HasPrefix(strings.TrimLeft(line, ">"), "From ") {
+			line = line[1:]
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("readMbox: %w", err)
+	}
+
+	if current != nil {
+		msgs = append(msgs, current.Bytes())
+	}
+
+	return msgs, nil
+}
+
+// isMboxSeparator checks line is "From ", the sender, then the date the
+// message was delivered
+func isMboxSeparator(line string) bool {
+	if !strings.HasPrefix(line, "From ") {
+		return false
+	}
+
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return false
+	}
+
+	date := fields[2:]
+	for _, layout := range mboxDateLayouts {
+		n := len(strings.Fields(layout))
+		if len(date) < n {
+			continue
+		}
+		if _, err := time.Parse(layout, strings.Join(date[:n], " ")); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func messageToItem(raw []byte) (Item, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return Item{}, fmt.Errorf("messageToItem: %w", err)
+	}
+
+	subject := decodeHeader(msg.Header.Get("Subject"))
+	if subject == "" {
+		subject = "(no subject)"
+	}
+
+	it := Item{
+		Title: subject,
+	}
+
+	if from, err := mail.ParseAddress(msg.Header.Get("From")); err == nil {
+		it.Author = from.Address
+		it.FeedName = from.Name
+		if from.Name == "" {
+			it.FeedName = from.Address
+		}
+	} else {
+		it.Author = decodeHeader(msg.Header.Get("From"))
+		it.FeedName = it.Author
+	}
+
+	if date, err := msg.Header.Date(); err == nil {
+		it.PubDate = date
+	}
+
+	if id := strings.Trim(msg.Header.Get("Message-Id"), "<> "); id != "" {
+		it.Link = "mid:" + id
+	}
+
+	htmlBody, textBody, err := readBody(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return Item{}, fmt.Errorf("messageToItem: %w", err)
+	}
+
+	if htmlBody != "" {
+		it.Content = htmlBody
+	} else {
+		it.Content = textToHTML(textBody)
+	}
+	it.Description = textBody
+
+	return it, nil
+}
+
+func decodeHeader(h string) string {
+	decoded, err := headerDecoder.DecodeHeader(h)
+	if err != nil {
+		return h
+	}
+	return decoded
+}
+
+// readBody walks a (possibly multipart) body and returns the first html and
+// plain text parts it finds.
+func readBody(contentType string, encoding string, body io.Reader) (htmlBody string, textBody string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// RFC 2045 default
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return htmlBody, textBody, fmt.Errorf("readBody: %w", err)
+			}
+
+			h, t, err := readBody(p.Header.Get("Content-Type"), p.Header.Get("Content-Transfer-Encoding"), p)
+			if err != nil {
+				return htmlBody, textBody, err
+			}
+			if htmlBody == "" {
+				htmlBody = h
+			}
+			if textBody == "" {
+				textBody = t
+			}
+		}
+
+		return htmlBody, textBody, nil
+	}
+
+	switch strings.ToLower(encoding) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", fmt.Errorf("readBody: %w", err)
+	}
+
+	switch mediaType {
+	case "text/html":
+		return string(b), "", nil
+	case "text/plain":
+		return "", string(b), nil
+	}
+
+	return "", "", nil
+}
+
+func textToHTML(text string) string {
+	var out strings.Builder
+	for _, para := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		out.WriteString("<p>")
+		out.WriteString(strings.ReplaceAll(html.EscapeString(para), "\n", "<br>"))
+		out.WriteString("</p>\n")
+	}
+	return out.String()
+}
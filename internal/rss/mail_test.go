@@ -0,0 +1,56 @@
+package rss
+
+import (
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+const maildirFixture = "../test/data/maildir"
+const mboxFixture = "../test/data/newsletters.mbox"
+
+func TestFetchMaildir(t *testing.T) {
+	r, err := Fetch(config.Feed{URL: maildirFixture, Type: config.FeedTypeMaildir}, nil, "test", "")
+	test.HandleError(t, err)
+
+	test.Equal(t, 2, len(r.Channel.Items), "missing messages")
+
+	html := r.Channel.Items[0]
+	test.Equal(t, "Issue 42 — generics", html.Title, "encoded subject not decoded")
+	test.Equal(t, "Weekly Go", html.FeedName, "sender should be the feed name")
+	test.Equal(t, "editor@weekly.example", html.Author, "bad author")
+	test.Equal(t, "mid:issue42@weekly.example", html.Link, "bad link")
+	test.Equal(t, "<h1>Issue 42</h1><p>All about =generics.</p>", html.Content, "html part not preferred")
+	test.Equal(t, int64(1700000000), html.PubDate.Unix(), "bad date")
+
+	plain := r.Channel.Items[1]
+	test.Equal(t, "plain@letters.example", plain.FeedName, "address should be used without a name")
+	test.Equal(t, "<p>First paragraph<br>continues here.</p>\n<p>Second &lt;paragraph&gt;.</p>\n", plain.Content, "plain text not converted")
+}
+
+func TestFetchMaildirFeedName(t *testing.T) {
+	r, err := Fetch(config.Feed{URL: maildirFixture, Name: "letters", Type: config.FeedTypeMaildir}, nil, "test", "")
+	test.HandleError(t, err)
+
+	test.Equal(t, "letters", r.Channel.Items[0].FeedName, "config name should win over sender")
+}
+
+func TestFetchMbox(t *testing.T) {
+	r, err := Fetch(config.Feed{URL: mboxFixture, Type: config.FeedTypeMbox}, nil, "test", "")
+	test.HandleError(t, err)
+
+	test.Equal(t, 3, len(r.Channel.Items), "missing messages")
+	test.Equal(t, "Issue 41", r.Channel.Items[0].Title, "bad title")
+	test.Equal(t, "<p>Hello from issue 41</p>", r.Channel.Items[0].Content, "base64 body not decoded")
+	test.Equal(t, "From the archives.\n", r.Channel.Items[1].Description, "From quoting not undone")
+	test.Equal(t, "Sent again with the links fixed.\n\nFrom the editor, with apologies.\n", r.Channel.Items[2].Description, "unquoted From line split the message")
+	test.Equal(t, "mid:issue41-resend@weekly.example", r.Channel.Items[2].Link, "bad link")
+}
+
+func TestIsMboxSeparator(t *testing.T) {
+	test.Equal(t, true, isMboxSeparator("From editor@weekly.example Tue Nov 21 22:13:20 2023"), "asctime date")
+	test.Equal(t, true, isMboxSeparator("From MAILER-DAEMON Fri Jul  8 12:08:34 +0100 2011"), "date with a zone")
+	test.Equal(t, false, isMboxSeparator("From the editor, with apologies."), "body text")
+	test.Equal(t, false, isMboxSeparator("From: editor@weekly.example"), "header")
+}
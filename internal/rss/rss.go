@@ -4,6 +4,8 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/mmcdole/gofeed"
@@ -20,6 +22,25 @@ type Item struct {
 	Content     string    `xml:"encoded"`
 	PubDate     time.Time `xml:"pubDate"`
 	FeedName    string
+	Enclosures  []Enclosure
+	ITunes      ITunes
+}
+
+// Enclosure is media attached to an item, e.g. a podcast episode
+type Enclosure struct {
+	URL      string
+	Type     string
+	Length   int64
+	Duration string
+}
+
+// ITunes holds the itunes: podcast metadata of an item
+type ITunes struct {
+	Duration string
+	Episode  string
+	Season   string
+	Image    string
+	Subtitle string
 }
 
 type Channel struct {
@@ -33,9 +54,42 @@ type RSS struct {
 	Channel Channel `xml:"channel"`
 }
 
-func Fetch(f config.Feed, httpOpts *config.HTTPOptions, version string) (RSS, error) {
+// Fetch retrieves and parses a feed. configDir is where state such as
+// pinned gemini certificates is kept.
+func Fetch(f config.Feed, httpOpts *config.HTTPOptions, version string, configDir string) (RSS, error) {
+	if f.IsMail() {
+		return fetchMail(f)
+	}
+
+	if IsGemini(f.URL) {
+		return fetchGemini(f, configDir)
+	}
+
+	client := newHTTPClient(httpOpts)
+
+	if f.Type == config.FeedTypeScrape {
+		return fetchScrape(f, client, version)
+	}
+
 	fp := gofeed.NewParser()
+	fp.Client = client
+	fp.UserAgent = userAgent(version)
+
+	feed, err := fp.ParseURL(f.URL)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.Fetch: %w", err)
+	}
+
+	rss := feedToRSS(f, feed)
 
+	return rss, nil
+}
+
+// requestTimeout is how long a request may take, so one slow site can't
+// hold up a refresh
+const requestTimeout = 30 * time.Second
+
+func newHTTPClient(httpOpts *config.HTTPOptions) *http.Client {
 	tr := &http.Transport{
 		Proxy: http.ProxyFromEnvironment,
 	}
@@ -48,20 +102,14 @@ func Fetch(f config.Feed, httpOpts *config.HTTPOptions, version string) (RSS, er
 		}
 	}
 
-	fp.Client = &http.Client{
+	return &http.Client{
 		Transport: tr,
+		Timeout:   requestTimeout,
 	}
+}
 
-	fp.UserAgent = fmt.Sprintf("nom/%s", version)
-
-	feed, err := fp.ParseURL(f.URL)
-	if err != nil {
-		return RSS{}, fmt.Errorf("rss.Fetch: %w", err)
-	}
-
-	rss := feedToRSS(f, feed)
-
-	return rss, nil
+func userAgent(version string) string {
+	return fmt.Sprintf("nom/%s", version)
 }
 
 func feedToRSS(f config.Feed, feed *gofeed.Feed) RSS {
@@ -92,6 +140,31 @@ func feedToRSS(f config.Feed, feed *gofeed.Feed) RSS {
 
 		ni.Categories = it.Categories
 
+		if it.ITunesExt != nil {
+			ni.ITunes = ITunes{
+				Duration: it.ITunesExt.Duration,
+				Episode:  it.ITunesExt.Episode,
+				Season:   it.ITunesExt.Season,
+				Image:    it.ITunesExt.Image,
+				Subtitle: it.ITunesExt.Subtitle,
+			}
+		}
+
+		for _, e := range it.Enclosures {
+			if e == nil || e.URL == "" {
+				continue
+			}
+
+			length, _ := strconv.ParseInt(strings.TrimSpace(e.Length), 10, 64)
+			ni.Enclosures = append(ni.Enclosures, Enclosure{
+				URL:    e.URL,
+				Type:   e.Type,
+				Length: length,
+				// itunes only has one duration, which belongs to the episode
+				Duration: ni.ITunes.Duration,
+			})
+		}
+
 		// PublishedParsed will be nil if parsing failed
 		if it.PublishedParsed != nil {
 			ni.PubDate = *it.PublishedParsed
@@ -14,6 +14,7 @@ import (
 
 const dropboxFixture = "../test/data/dropbox_fixture.rss"
 const badPubDateFixture = "../test/data/bad_pub_date.rss"
+const podcastFixture = "../test/data/podcast_fixture.rss"
 
 func getFixtureAsFeed(path string) (*gofeed.Feed, error) {
 	f, err := os.ReadFile(path)
@@ -65,3 +66,24 @@ func TestFeedPubDateBadParse(t *testing.T) {
 	r := feedToRSS(config.Feed{}, fd)
 	test.Equal(t, "0001-01-01 00:00:00 +0000 UTC", r.Channel.Items[0].PubDate.String(), "dates don't match")
 }
+
+func TestFeedToRSSEnclosures(t *testing.T) {
+	fd, err := getFixtureAsFeed(podcastFixture)
+	test.HandleError(t, err)
+
+	r := feedToRSS(config.Feed{}, fd)
+
+	episode := r.Channel.Items[0]
+	test.Equal(t, 1, len(episode.Enclosures), "missing enclosure")
+	test.Equal(t, "https://cdn.example.com/gotime-300.mp3", episode.Enclosures[0].URL, "bad enclosure url")
+	test.Equal(t, "audio/mpeg", episode.Enclosures[0].Type, "bad enclosure type")
+	test.Equal(t, int64(34567890), episode.Enclosures[0].Length, "bad enclosure length")
+	test.Equal(t, "01:02:03", episode.Enclosures[0].Duration, "bad enclosure duration")
+
+	test.Equal(t, "300", episode.ITunes.Episode, "bad episode")
+	test.Equal(t, "6", episode.ITunes.Season, "bad season")
+	test.Equal(t, "Generics, two years on", episode.ITunes.Subtitle, "bad subtitle")
+	test.Equal(t, "https://cdn.example.com/300.jpg", episode.ITunes.Image, "bad image")
+
+	test.Equal(t, 0, len(r.Channel.Items[1].Enclosures), "unexpected enclosure")
+}
@@ -0,0 +1,159 @@
+package rss
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+)
+
+// Layouts tried, in order, when a scrape feed has no dateFormat set.
+var scrapeDateLayouts = []string{
+	time.RFC3339,
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"02/01/2006",
+}
+
+func fetchScrape(f config.Feed, client *http.Client, version string) (RSS, error) {
+	if f.Scrape == nil || f.Scrape.Item == "" || f.Scrape.Title == "" {
+		return RSS{}, fmt.Errorf("rss.fetchScrape: %s: scrape feeds need at least item and title selectors", f.URL)
+	}
+
+	base, err := url.Parse(f.URL)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchScrape: %w", err)
+	}
+
+	req, err := http.NewRequest("GET", f.URL, nil)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchScrape: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent(version))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.fetchScrape: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return RSS{}, fmt.Errorf("rss.fetchScrape: %s returned status %d", f.URL, resp.StatusCode)
+	}
+
+	return scrapeToRSS(f, base, resp.Body)
+}
+
+// scrapeToRSS turns the elements of an HTML document matching the feed's
+// selectors into items.
+func scrapeToRSS(f config.Feed, base *url.URL, r io.Reader) (RSS, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return RSS{}, fmt.Errorf("rss.scrapeToRSS: %w", err)
+	}
+
+	sel := f.Scrape
+	items := make([]Item, 0)
+
+	doc.Find(sel.Item).Each(func(_ int, s *goquery.Selection) {
+		title := strings.TrimSpace(s.Find(sel.Title).First().Text())
+		if title == "" {
+			return
+		}
+
+		ni := Item{
+			Title:    title,
+			Link:     scrapeLink(s, sel.Link, base),
+			FeedName: f.Name,
+		}
+
+		content := s
+		if sel.Content != "" {
+			content = s.Find(sel.Content).First()
+		}
+		if html, err := content.Html(); err == nil {
+			ni.Content = strings.TrimSpace(html)
+			ni.Description = strings.TrimSpace(content.Text())
+		}
+
+		if sel.Date != "" {
+			ni.PubDate = scrapeDate(s.Find(sel.Date).First(), sel.DateFormat)
+		}
+
+		items = append(items, ni)
+	})
+
+	rss := RSS{}
+	rss.Channel = Channel{
+		Title:       strings.TrimSpace(doc.Find("title").First().Text()),
+		Link:        base.String(),
+		Description: doc.Find(`meta[name="description"]`).AttrOr("content", ""),
+		Items:       items,
+	}
+
+	return rss, nil
+}
+
+// scrapeLink finds the href for an item. Without a link selector the item
+// itself, or the first anchor inside it, is used.
+func scrapeLink(s *goquery.Selection, selector string, base *url.URL) string {
+	var a *goquery.Selection
+	switch {
+	case selector != "":
+		a = s.Find(selector).First()
+	case goquery.NodeName(s) == "a":
+		a = s
+	default:
+		a = s.Find("a[href]").First()
+	}
+
+	href, ok := a.Attr("href")
+	if !ok {
+		return ""
+	}
+
+	ref, err := url.Parse(strings.TrimSpace(href))
+	if err != nil {
+		return href
+	}
+
+	return base.ResolveReference(ref).String()
+}
+
+// scrapeDate prefers a machine readable datetime attribute, as used by
+// <time>, and falls back to the element text.
+func scrapeDate(s *goquery.Selection, layout string) time.Time {
+	raw, ok := s.Attr("datetime")
+	if !ok {
+		raw = s.Text()
+	}
+	raw = strings.TrimSpace(raw)
+
+	layouts := scrapeDateLayouts
+	if layout != "" {
+		layouts = []string{layout}
+	}
+
+	for _, l := range layouts {
+		if t, err := time.Parse(l, raw); err == nil {
+			return t
+		}
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,77 @@
+package rss
+
+import (
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/config"
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+const scrapeFixture = "../test/data/scrape_fixture.html"
+
+func TestScrapeToRSS(t *testing.T) {
+	f, err := os.Open(scrapeFixture)
+	test.HandleError(t, err)
+	defer f.Close()
+
+	base, _ := url.Parse("https://acme.example/changelog")
+	feed := config.Feed{
+		Name: "acme",
+		Type: config.FeedTypeScrape,
+		Scrape: &config.ScrapeOptions{
+			Item:    "article.entry",
+			Title:   ".entry-title",
+			Date:    "time",
+			Content: ".entry-body",
+		},
+	}
+
+	r, err := scrapeToRSS(feed, base, f)
+	test.HandleError(t, err)
+
+	test.Equal(t, "Acme Changelog", r.Channel.Title, "bad channel title")
+	test.Equal(t, "Release notes for Acme", r.Channel.Description, "bad channel description")
+	test.Equal(t, 2, len(r.Channel.Items), "untitled items should be skipped")
+
+	first := r.Channel.Items[0]
+	test.Equal(t, "v2.1 released", first.Title, "bad title")
+	test.Equal(t, "https://acme.example/changelog/v2.1", first.Link, "relative link not resolved")
+	test.Equal(t, "<p>Faster <strong>sync</strong>.</p>", first.Content, "bad content")
+	test.Equal(t, int64(1709632800), first.PubDate.Unix(), "datetime attribute not used")
+	test.Equal(t, "acme", first.FeedName, "bad feedname")
+
+	second := r.Channel.Items[1]
+	test.Equal(t, "https://acme.example/changelog/v2.0", second.Link, "absolute link changed")
+	test.Equal(t, "2024-01-02", second.PubDate.Format("2006-01-02"), "text date not parsed")
+}
+
+func TestScrapeDateFormat(t *testing.T) {
+	f, err := os.Open(scrapeFixture)
+	test.HandleError(t, err)
+	defer f.Close()
+
+	base, _ := url.Parse("https://acme.example/")
+	feed := config.Feed{
+		Scrape: &config.ScrapeOptions{
+			Item:       "article.entry",
+			Title:      ".entry-title",
+			Link:       ".entry-title a",
+			Date:       "time",
+			DateFormat: "2006",
+		},
+	}
+
+	r, err := scrapeToRSS(feed, base, f)
+	test.HandleError(t, err)
+
+	test.Equal(t, true, r.Channel.Items[0].PubDate.IsZero(), "explicit dateFormat should not fall back")
+}
+
+func TestFetchScrapeWithoutSelectors(t *testing.T) {
+	_, err := Fetch(config.Feed{URL: "http://localhost", Type: config.FeedTypeScrape}, nil, "test", "")
+	if err == nil {
+		t.Fatal("expected an error for a scrape feed without selectors")
+	}
+}
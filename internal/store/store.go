@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
@@ -19,13 +21,39 @@ type Item struct {
 	Title       string
 	Favourite   bool
 	FeedURL     string
-	FeedName    string // added from config if set
+	FeedName    string // added from config if set, or the sender for mail feeds
 	Link        string
 	Content     string
 	ReadAt      time.Time
 	PublishedAt time.Time
 	UpdatedAt   time.Time
 	CreatedAt   time.Time
+	Enclosures  []Enclosure // only loaded by GetItemByID
+	Categories  []string    // supplied by the feed
+	Tags        []string    // added by the user
+	ReadLater   bool
+	FullText    string   // extracted from Link, only loaded by GetItemByID
+	Gemtext     string   // fetched from a gemini Link, only loaded by GetItemByID
+	Note        string   // markdown written by the user
+	Score       int      // from the scoring rules in config
+	Cluster     int      // the item ID of the first copy of the same story, 0 if none
+	AlsoIn      []string // feeds with copies of the story, set when clusters are collapsed
+	ITunes      ITunes
+}
+
+type Enclosure struct {
+	URL      string
+	Type     string
+	Length   int64
+	Duration string
+}
+
+type ITunes struct {
+	Duration string
+	Episode  string
+	Season   string
+	Image    string
+	Subtitle string
 }
 
 func (i Item) Read() bool {
@@ -33,17 +61,41 @@ func (i Item) Read() bool {
 }
 
 type Store interface {
-	UpsertItem(item Item) error
+	UpsertItem(item Item) (int, bool, error)
+	DeleteItem(item Item) error
 	BeginBatch() error
 	EndBatch() error
 	GetAllItems(ordering string) ([]Item, error)
 	GetItemByID(ID int) (Item, error)
+	GetItemsByTag(tag string, ordering string) ([]Item, error)
+	AddTag(ID int, tag string) error
+	RemoveTag(ID int, tag string) error
+	ListTags() ([]string, error)
+	AddToReadLater(ID int) error
+	RemoveFromReadLater(ID int) error
+	GetReadLaterItems() ([]Item, error)
+	MoveReadLater(ID int, offset int) error
+	SetFullText(ID int, text string) error
+	GetItemsWithoutFullText(feedURL string) ([]Item, error)
+	SetGemtext(ID int, text string) error
+	GetItemsWithoutGemtext(feedURL string) ([]Item, error)
+	SetNote(ID int, note string) error
+	SetScores(scores map[int]int) error
+	SetClusters(clusters map[int]int) error
+	SetClusterRead(cluster int, read bool) error
 	GetAllFeedURLs() ([]string, error)
 	ToggleRead(ID int) error
 	MarkAllRead() error
 	ToggleFavourite(ID int) error
 	DeleteByFeedURL(feedurl string, incFavourites bool) error
 	CountUnread() (int, error)
+	CountUnreadByFeed() (map[string]int, error)
+	SetRead(IDs []int, read bool) error
+	SetFavourite(IDs []int, favourite bool) error
+	SetTag(IDs []int, tag string, on bool) error
+	SetReadAt(readAt map[int]time.Time) error
+	GetItemStates(IDs []int) ([]Item, error)
+	MarkReadWhere(match func(Item) bool) error
 }
 
 type SQLiteStore struct {
@@ -114,6 +166,23 @@ func runMigrations(db *sql.DB) (err error) {
 	// Index based so all new migrations must go at the end of the array
 	migrations := []string{
 		`alter table items add favourite boolean not null default 0;`,
+		`alter table items add feedname text;`,
+		`create table enclosures (id integer primary key, itemid integer not null, url text not null, type text, length integer, duration text);`,
+		`alter table items add itunesduration text;`,
+		`alter table items add itunesepisode text;`,
+		`alter table items add itunesseason text;`,
+		`alter table items add itunesimage text;`,
+		`alter table items add itunessubtitle text;`,
+		`create table tags (id integer primary key, name text not null unique collate nocase);`,
+		`create table itemtags (itemid integer not null, tagid integer not null, primary key (itemid, tagid));`,
+		`alter table itemtags add user boolean not null default 0;`,
+		`create table readlater (itemid integer primary key, position integer not null, addedat datetime);`,
+		`alter table items add fulltext text;`,
+		`create table notes (itemid integer primary key, note text not null, updatedat datetime);`,
+		`alter table items add score integer not null default 0;`,
+		`alter table items add cluster integer;`,
+		`alter table items add gemtext text;`,
+		`create index itemscluster on items (cluster);`,
 	}
 
 	tx, _ := db.Begin()
@@ -177,7 +246,8 @@ func (sls *SQLiteStore) EndBatch() error {
 	return nil
 }
 
-func (sls *SQLiteStore) UpsertItem(item Item) error {
+// UpsertItem stores an item, returning its ID and whether it is new
+func (sls *SQLiteStore) UpsertItem(item Item) (int, bool, error) {
 	if sls.batch != nil {
 		return sls.upsertItem(sls.batch, item)
 	}
@@ -189,61 +259,216 @@ type statementPreparer interface {
 	Prepare(query string) (*sql.Stmt, error)
 }
 
-func (sls *SQLiteStore) upsertItem(db statementPreparer, item Item) error {
-	stmt, err := db.Prepare(`select count(id), id from items where feedurl = ? and title = ?;`)
+// itemKey is the column and value that tell an item apart from others in
+// its feed. Newsletters reuse subjects, so mail goes by its Message-Id.
+func itemKey(item Item) (string, string) {
+	if strings.HasPrefix(item.Link, "mid:") {
+		return "link", item.Link
+	}
+
+	return "title", item.Title
+}
+
+func (sls *SQLiteStore) upsertItem(db statementPreparer, item Item) (int, bool, error) {
+	key, value := itemKey(item)
+
+	stmt, err := db.Prepare(`select count(id), id from items where feedurl = ? and ` + key + ` = ?;`)
 	if err != nil {
-		return fmt.Errorf("sqlite.go: could not prepare query: %w", err)
+		return 0, false, fmt.Errorf("sqlite.go: could not prepare query: %w", err)
 	}
 
 	var count int
 	var id sql.NullInt32
-	err = stmt.QueryRow(item.FeedURL, item.Title).Scan(&count, &id)
+	err = stmt.QueryRow(item.FeedURL, value).Scan(&count, &id)
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
-		return fmt.Errorf("store.go: write %w", err)
+		return 0, false, fmt.Errorf("store.go: write %w", err)
 	}
 
+	itemID := int64(id.Int32)
+
 	if count == 0 {
-		stmt, err = db.Prepare(`insert into items (feedurl, link, title, content, author, publishedat, createdat, updatedat) values (?, ?, ?, ?, ?, ?, ?, ?)`)
+		stmt, err = db.Prepare(`insert into items (feedurl, feedname, link, title, content, author, publishedat, createdat, updatedat, itunesduration, itunesepisode, itunesseason, itunesimage, itunessubtitle) values (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`)
 		if err != nil {
-			return fmt.Errorf("sqlite.go: could not prepare query: %w", err)
+			return 0, false, fmt.Errorf("sqlite.go: could not prepare query: %w", err)
 		}
 
-		_, err = stmt.Exec(item.FeedURL, item.Link, item.Title, item.Content, item.Author, item.PublishedAt, time.Now(), time.Now())
+		res, err := stmt.Exec(item.FeedURL, item.FeedName, item.Link, item.Title, item.Content, item.Author, item.PublishedAt, time.Now(), time.Now(), item.ITunes.Duration, item.ITunes.Episode, item.ITunes.Season, item.ITunes.Image, item.ITunes.Subtitle)
 		if err != nil {
-			return fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+			return 0, false, fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+		}
+
+		itemID, err = res.LastInsertId()
+		if err != nil {
+			return 0, false, fmt.Errorf("sqlite.go: Upsert failed: %w", err)
 		}
 	} else {
-		stmt, err = db.Prepare(`update items set content = ?, updatedat = ? where id = ?`)
+		stmt, err = db.Prepare(`update items set content = ?, updatedat = ?, itunesduration = ?, itunesepisode = ?, itunesseason = ?, itunesimage = ?, itunessubtitle = ? where id = ?`)
+		if err != nil {
+			return 0, false, fmt.Errorf("sqlite.go: could not prepare query: %w", err)
+		}
+
+		_, err = stmt.Exec(item.Content, time.Now(), item.ITunes.Duration, item.ITunes.Episode, item.ITunes.Season, item.ITunes.Image, item.ITunes.Subtitle, id)
+		if err != nil {
+			return 0, false, fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+		}
+	}
+
+	err = setEnclosures(db, itemID, item.Enclosures)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+	}
+
+	err = setCategories(db, itemID, item.Categories)
+	if err != nil {
+		return 0, false, fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+	}
+
+	return int(itemID), count == 0, nil
+}
+
+// DeleteItem removes a stored item, found as UpsertItem finds it, unless it
+// is a favourite, has a note or is saved for later. Like UpsertItem it uses
+// the batch transaction if there is one.
+func (sls *SQLiteStore) DeleteItem(item Item) error {
+	var db statementPreparer = sls.db
+	if sls.batch != nil {
+		db = sls.batch
+	}
+
+	key, value := itemKey(item)
+	match := `feedurl = ? and ` + key + ` = ? and favourite = false and id not in (select itemid from notes) and id not in (select itemid from readlater)`
+
+	for _, q := range []string{
+		`delete from enclosures where itemid in (select id from items where ` + match + `);`,
+		`delete from itemtags where itemid in (select id from items where ` + match + `);`,
+		`delete from items where ` + match + `;`,
+	} {
+		stmt, err := db.Prepare(q)
 		if err != nil {
-			return fmt.Errorf("sqlite.go: could not prepare query: %w", err)
+			return fmt.Errorf("[store.go] DeleteItem: %w", err)
 		}
 
-		_, err = stmt.Exec(item.Content, time.Now(), id)
+		_, err = stmt.Exec(item.FeedURL, value)
 		if err != nil {
-			return fmt.Errorf("sqlite.go: Upsert failed: %w", err)
+			return fmt.Errorf("[store.go] DeleteItem: %w", err)
 		}
 	}
 
 	return nil
 }
 
+// setEnclosures replaces the enclosures of an item
+func setEnclosures(db statementPreparer, itemID int64, enclosures []Enclosure) error {
+	stmt, err := db.Prepare(`delete from enclosures where itemid = ?;`)
+	if err != nil {
+		return fmt.Errorf("setEnclosures: %w", err)
+	}
+
+	_, err = stmt.Exec(itemID)
+	if err != nil {
+		return fmt.Errorf("setEnclosures: %w", err)
+	}
+
+	if len(enclosures) == 0 {
+		return nil
+	}
+
+	stmt, err = db.Prepare(`insert into enclosures (itemid, url, type, length, duration) values (?, ?, ?, ?, ?);`)
+	if err != nil {
+		return fmt.Errorf("setEnclosures: %w", err)
+	}
+
+	for _, e := range enclosures {
+		_, err = stmt.Exec(itemID, e.URL, e.Type, e.Length, e.Duration)
+		if err != nil {
+			return fmt.Errorf("setEnclosures: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (sls SQLiteStore) getEnclosures(itemID int) ([]Enclosure, error) {
+	var enclosures []Enclosure
+
+	stmt, _ := sls.db.Prepare(`select url, type, length, duration from enclosures where itemid = ? order by id;`)
+
+	rows, err := stmt.Query(itemID)
+	if err != nil {
+		return enclosures, fmt.Errorf("[store.go] getEnclosures: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e Enclosure
+		var typeNull, durationNull sql.NullString
+		var lengthNull sql.NullInt64
+
+		err := rows.Scan(&e.URL, &typeNull, &lengthNull, &durationNull)
+		if err != nil {
+			return enclosures, fmt.Errorf("[store.go] getEnclosures: %w", err)
+		}
+
+		e.Type = typeNull.String
+		e.Length = lengthNull.Int64
+		e.Duration = durationNull.String
+
+		enclosures = append(enclosures, e)
+	}
+
+	return enclosures, nil
+}
+
 // TODO: pagination
 func (sls SQLiteStore) GetAllItems(ordering string) ([]Item, error) {
-	itemStmt := `
-		select id, feedurl, link, title, content, author, readat, favourite, publishedat, createdat, updatedat from items order by coalesce(publishedat, createdat) %s;
-	`
+	items, err := sls.getItems("", ordering)
+	if err != nil {
+		return []Item{}, fmt.Errorf("store.go: GetAllItems: %w", err)
+	}
 
-	var stmt string
+	return items, nil
+}
+
+// GetItemsByTag returns all items, read or not, that have tag
+func (sls SQLiteStore) GetItemsByTag(tag string, ordering string) ([]Item, error) {
+	items, err := sls.getItems(`where items.id in (select itemid from itemtags join tags on tags.id = itemtags.tagid where tags.name = ?)`, ordering, tag)
+	if err != nil {
+		return []Item{}, fmt.Errorf("store.go: GetItemsByTag: %w", err)
+	}
+
+	return items, nil
+}
+
+// getItems selects items matching the where clause, along with their tags
+func (sls SQLiteStore) getItems(where string, ordering string, args ...any) ([]Item, error) {
+	var order string
 	switch ordering {
 	case constants.DescendingOrdering:
-		stmt = fmt.Sprintf(itemStmt, constants.DescendingOrdering)
+		order = "coalesce(publishedat, createdat) " + constants.DescendingOrdering
+	case constants.ScoreOrdering:
+		order = "score desc, coalesce(publishedat, createdat) desc"
 	default:
-		stmt = fmt.Sprintf(itemStmt, constants.DefaultOrdering)
+		order = "coalesce(publishedat, createdat) " + constants.DefaultOrdering
 	}
 
-	rows, err := sls.db.Query(stmt)
+	return sls.queryItems(where, order, args...)
+}
+
+func (sls SQLiteStore) queryItems(where string, order string, args ...any) ([]Item, error) {
+	itemStmt := `
+		select items.id, feedurl, feedname, link, title, content, author, readat, favourite, publishedat, createdat, items.updatedat, readlater.itemid is not null, coalesce(notes.note, ''), score, coalesce(cluster, 0) from items left join readlater on readlater.itemid = items.id left join notes on notes.itemid = items.id %s order by %s;
+	`
+
+	stmt := fmt.Sprintf(itemStmt, where, order)
+
+	tags, err := sls.getAllTags()
 	if err != nil {
-		return []Item{}, fmt.Errorf("store.go: GetAllItems: %w", err)
+		return []Item{}, err
+	}
+
+	rows, err := sls.db.Query(stmt, args...)
+	if err != nil {
+		return []Item{}, err
 	}
 	defer rows.Close()
 
@@ -253,15 +478,21 @@ func (sls SQLiteStore) GetAllItems(ordering string) ([]Item, error) {
 		var readAtNull sql.NullTime
 		var publishedAtNull sql.NullTime
 		var linkNull sql.NullString
+		var feedNameNull sql.NullString
 
-		if err := rows.Scan(&item.ID, &item.FeedURL, &linkNull, &item.Title, &item.Content, &item.Author, &readAtNull, &item.Favourite, &publishedAtNull, &item.CreatedAt, &item.UpdatedAt); err != nil {
+		if err := rows.Scan(&item.ID, &item.FeedURL, &feedNameNull, &linkNull, &item.Title, &item.Content, &item.Author, &readAtNull, &item.Favourite, &publishedAtNull, &item.CreatedAt, &item.UpdatedAt, &item.ReadLater, &item.Note, &item.Score, &item.Cluster); err != nil {
 			fmt.Println("errrerre: ", err)
 			continue
 		}
 
 		item.Link = linkNull.String
+		item.FeedName = feedNameNull.String
 		item.ReadAt = readAtNull.Time
 		item.PublishedAt = publishedAtNull.Time
+		if t, ok := tags[item.ID]; ok {
+			item.Categories = t.categories
+			item.Tags = t.tags
+		}
 
 		items = append(items, item)
 	}
@@ -269,6 +500,169 @@ func (sls SQLiteStore) GetAllItems(ordering string) ([]Item, error) {
 	return items, nil
 }
 
+type itemTags struct {
+	categories []string
+	tags       []string
+}
+
+func (it *itemTags) add(name string, user bool) {
+	if user {
+		it.tags = append(it.tags, name)
+	} else {
+		it.categories = append(it.categories, name)
+	}
+}
+
+// getAllTags returns the tag names of every tagged item, keyed by item ID
+func (sls SQLiteStore) getAllTags() (map[int]*itemTags, error) {
+	tags := map[int]*itemTags{}
+
+	rows, err := sls.db.Query(`select itemtags.itemid, tags.name, itemtags.user from itemtags join tags on tags.id = itemtags.tagid order by tags.name;`)
+	if err != nil {
+		return tags, fmt.Errorf("getAllTags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int
+		var name string
+		var user bool
+
+		err := rows.Scan(&id, &name, &user)
+		if err != nil {
+			return tags, fmt.Errorf("getAllTags: %w", err)
+		}
+
+		if tags[id] == nil {
+			tags[id] = &itemTags{}
+		}
+		tags[id].add(name, user)
+	}
+
+	return tags, nil
+}
+
+func (sls SQLiteStore) getTags(itemID int) (*itemTags, error) {
+	tags := &itemTags{}
+
+	stmt, _ := sls.db.Prepare(`select tags.name, itemtags.user from itemtags join tags on tags.id = itemtags.tagid where itemtags.itemid = ? order by tags.name;`)
+
+	rows, err := stmt.Query(itemID)
+	if err != nil {
+		return tags, fmt.Errorf("getTags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		var user bool
+
+		err := rows.Scan(&name, &user)
+		if err != nil {
+			return tags, fmt.Errorf("getTags: %w", err)
+		}
+
+		tags.add(name, user)
+	}
+
+	return tags, nil
+}
+
+func (sls SQLiteStore) AddTag(ID int, tag string) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return fmt.Errorf("[store.go] AddTag: empty tag")
+	}
+
+	_, err := sls.db.Exec(`insert or ignore into tags (name) values (?);`, tag)
+	if err != nil {
+		return fmt.Errorf("[store.go] AddTag: %w", err)
+	}
+
+	// a user tag replaces the same feed category so it survives refreshes
+	_, err = sls.db.Exec(`insert or replace into itemtags (itemid, tagid, user) select ?, id, 1 from tags where name = ?;`, ID, tag)
+	if err != nil {
+		return fmt.Errorf("[store.go] AddTag: %w", err)
+	}
+
+	return nil
+}
+
+func (sls SQLiteStore) RemoveTag(ID int, tag string) error {
+	_, err := sls.db.Exec(`delete from itemtags where itemid = ? and user = 1 and tagid in (select id from tags where name = ?);`, ID, tag)
+	if err != nil {
+		return fmt.Errorf("[store.go] RemoveTag: %w", err)
+	}
+
+	return nil
+}
+
+// ListTags returns the names of all tags in use, user tags and categories
+func (sls SQLiteStore) ListTags() ([]string, error) {
+	var tags []string
+
+	rows, err := sls.db.Query(`select name from tags where id in (select tagid from itemtags) order by name;`)
+	if err != nil {
+		return tags, fmt.Errorf("[store.go] ListTags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+
+		err := rows.Scan(&name)
+		if err != nil {
+			return tags, fmt.Errorf("[store.go] ListTags: %w", err)
+		}
+
+		tags = append(tags, name)
+	}
+
+	return tags, nil
+}
+
+// setCategories replaces the feed supplied tags of an item
+func setCategories(db statementPreparer, itemID int64, categories []string) error {
+	stmt, err := db.Prepare(`delete from itemtags where itemid = ? and user = 0;`)
+	if err != nil {
+		return fmt.Errorf("setCategories: %w", err)
+	}
+
+	_, err = stmt.Exec(itemID)
+	if err != nil {
+		return fmt.Errorf("setCategories: %w", err)
+	}
+
+	insertTag, err := db.Prepare(`insert or ignore into tags (name) values (?);`)
+	if err != nil {
+		return fmt.Errorf("setCategories: %w", err)
+	}
+
+	insertItemTag, err := db.Prepare(`insert or ignore into itemtags (itemid, tagid) select ?, id from tags where name = ?;`)
+	if err != nil {
+		return fmt.Errorf("setCategories: %w", err)
+	}
+
+	for _, c := range categories {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+
+		_, err = insertTag.Exec(c)
+		if err != nil {
+			return fmt.Errorf("setCategories: %w", err)
+		}
+
+		_, err = insertItemTag.Exec(itemID, c)
+		if err != nil {
+			return fmt.Errorf("setCategories: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (sls SQLiteStore) ToggleRead(ID int) error {
 	stmt, _ := sls.db.Prepare(`update items set readat = case when readat is null then ? else null end where id = ?`)
 
@@ -302,6 +696,214 @@ func (sls SQLiteStore) ToggleFavourite(ID int) error {
 	return nil
 }
 
+// AddToReadLater puts an item at the end of the read later queue
+func (sls SQLiteStore) AddToReadLater(ID int) error {
+	_, err := sls.db.Exec(`insert or ignore into readlater (itemid, position, addedat) select ?, coalesce(max(position), 0) + 1, ? from readlater;`, ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("[store.go] AddToReadLater: %w", err)
+	}
+
+	return nil
+}
+
+func (sls SQLiteStore) RemoveFromReadLater(ID int) error {
+	_, err := sls.db.Exec(`delete from readlater where itemid = ?;`, ID)
+	if err != nil {
+		return fmt.Errorf("[store.go] RemoveFromReadLater: %w", err)
+	}
+
+	return nil
+}
+
+// GetReadLaterItems returns the read later queue, read or not, in order
+func (sls SQLiteStore) GetReadLaterItems() ([]Item, error) {
+	items, err := sls.queryItems(`where readlater.itemid is not null`, `readlater.position asc`)
+	if err != nil {
+		return []Item{}, fmt.Errorf("[store.go] GetReadLaterItems: %w", err)
+	}
+
+	return items, nil
+}
+
+// MoveReadLater moves an item offset places through the read later queue,
+// negative offsets moving it towards the front.
+func (sls SQLiteStore) MoveReadLater(ID int, offset int) error {
+	tx, err := sls.db.Begin()
+	if err != nil {
+		return fmt.Errorf("[store.go] MoveReadLater: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`select itemid from readlater order by position asc;`)
+	if err != nil {
+		return fmt.Errorf("[store.go] MoveReadLater: %w", err)
+	}
+
+	var queue []int
+	from := -1
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return fmt.Errorf("[store.go] MoveReadLater: %w", err)
+		}
+		if id == ID {
+			from = len(queue)
+		}
+		queue = append(queue, id)
+	}
+	rows.Close()
+
+	if from == -1 {
+		return fmt.Errorf("[store.go] MoveReadLater: item %d is not in the queue", ID)
+	}
+
+	to := min(max(from+offset, 0), len(queue)-1)
+	queue = append(queue[:from], queue[from+1:]...)
+	queue = append(queue[:to], append([]int{ID}, queue[to:]...)...)
+
+	for position, id := range queue {
+		_, err = tx.Exec(`update readlater set position = ? where itemid = ?;`, position+1, id)
+		if err != nil {
+			return fmt.Errorf("[store.go] MoveReadLater: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (sls SQLiteStore) SetFullText(ID int, text string) error {
+	_, err := sls.db.Exec(`update items set fulltext = ? where id = ?;`, text, ID)
+	if err != nil {
+		return fmt.Errorf("[store.go] SetFullText: %w", err)
+	}
+
+	return nil
+}
+
+// GetItemsWithoutFullText returns the items of a feed that have never had
+// their full text fetched
+func (sls SQLiteStore) GetItemsWithoutFullText(feedURL string) ([]Item, error) {
+	items, err := sls.queryItems(`where feedurl = ? and items.fulltext is null`, `coalesce(publishedat, createdat) desc`, feedURL)
+	if err != nil {
+		return []Item{}, fmt.Errorf("[store.go] GetItemsWithoutFullText: %w", err)
+	}
+
+	return items, nil
+}
+
+func (sls SQLiteStore) SetGemtext(ID int, text string) error {
+	_, err := sls.db.Exec(`update items set gemtext = ? where id = ?;`, text, ID)
+	if err != nil {
+		return fmt.Errorf("[store.go] SetGemtext: %w", err)
+	}
+
+	return nil
+}
+
+// GetItemsWithoutGemtext returns the items of a feed that have never had
+// the gemtext they link to fetched
+func (sls SQLiteStore) GetItemsWithoutGemtext(feedURL string) ([]Item, error) {
+	items, err := sls.queryItems(`where feedurl = ? and items.gemtext is null`, `coalesce(publishedat, createdat) desc`, feedURL)
+	if err != nil {
+		return []Item{}, fmt.Errorf("[store.go] GetItemsWithoutGemtext: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetNote replaces the note on an item, an empty note removes it
+func (sls SQLiteStore) SetNote(ID int, note string) error {
+	var err error
+	if strings.TrimSpace(note) == "" {
+		_, err = sls.db.Exec(`delete from notes where itemid = ?;`, ID)
+	} else {
+		_, err = sls.db.Exec(`insert or replace into notes (itemid, note, updatedat) values (?, ?, ?);`, ID, note, time.Now())
+	}
+	if err != nil {
+		return fmt.Errorf("[store.go] SetNote: %w", err)
+	}
+
+	return nil
+}
+
+// SetScores stores item scores, keyed by item ID
+func (sls SQLiteStore) SetScores(scores map[int]int) error {
+	tx, err := sls.db.Begin()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetScores: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`update items set score = ? where id = ? and score != ?;`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("[store.go] SetScores: %w", err)
+	}
+
+	for id, score := range scores {
+		if _, err := stmt.Exec(score, id, score); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("[store.go] SetScores: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetScores: %w", err)
+	}
+
+	return nil
+}
+
+// SetClusters replaces the clusters of duplicate items, keyed by item ID
+func (sls SQLiteStore) SetClusters(clusters map[int]int) error {
+	tx, err := sls.db.Begin()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetClusters: %w", err)
+	}
+
+	_, err = tx.Exec(`update items set cluster = null where cluster is not null;`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("[store.go] SetClusters: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`update items set cluster = ? where id = ?;`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("[store.go] SetClusters: %w", err)
+	}
+
+	for id, cluster := range clusters {
+		if _, err := stmt.Exec(cluster, id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("[store.go] SetClusters: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetClusters: %w", err)
+	}
+
+	return nil
+}
+
+// SetClusterRead marks every item in a cluster read or unread
+func (sls SQLiteStore) SetClusterRead(cluster int, read bool) error {
+	var err error
+	if read {
+		_, err = sls.db.Exec(`update items set readat = ? where cluster = ? and readat is null;`, time.Now(), cluster)
+	} else {
+		_, err = sls.db.Exec(`update items set readat = null where cluster = ?;`, cluster)
+	}
+	if err != nil {
+		return fmt.Errorf("[store.go] SetClusterRead: %w", err)
+	}
+
+	return nil
+}
+
 func (sls SQLiteStore) GetAllFeedURLs() ([]string, error) {
 	var urls []string
 
@@ -340,28 +942,74 @@ func (sls SQLiteStore) DeleteByFeedURL(feedurl string, incFavourites bool) error
 		return fmt.Errorf("[store.go] DeleteByFeedURL: %w", err)
 	}
 
+	_, err = sls.db.Exec(`delete from enclosures where itemid not in (select id from items);`)
+	if err != nil {
+		return fmt.Errorf("[store.go] DeleteByFeedURL: %w", err)
+	}
+
+	_, err = sls.db.Exec(`delete from itemtags where itemid not in (select id from items);`)
+	if err != nil {
+		return fmt.Errorf("[store.go] DeleteByFeedURL: %w", err)
+	}
+
+	_, err = sls.db.Exec(`delete from readlater where itemid not in (select id from items);`)
+	if err != nil {
+		return fmt.Errorf("[store.go] DeleteByFeedURL: %w", err)
+	}
+
+	_, err = sls.db.Exec(`delete from notes where itemid not in (select id from items);`)
+	if err != nil {
+		return fmt.Errorf("[store.go] DeleteByFeedURL: %w", err)
+	}
+
 	return nil
 }
 
 func (sls SQLiteStore) GetItemByID(ID int) (Item, error) {
 	var stmt *sql.Stmt
-	stmt, _ = sls.db.Prepare(`select id, feedurl, link, title, content, author, readat, favourite, publishedat, createdat, updatedat from items where id = ?;`)
+	stmt, _ = sls.db.Prepare(`select id, feedurl, feedname, link, title, content, author, readat, favourite, publishedat, createdat, updatedat, itunesduration, itunesepisode, itunesseason, itunesimage, itunessubtitle, fulltext, gemtext, exists(select 1 from readlater where itemid = items.id), coalesce((select note from notes where itemid = items.id), ''), score, coalesce(cluster, 0) from items where id = ?;`)
 
 	var i Item
 	var readAtNull sql.NullTime
 	var publishedAtNull sql.NullTime
 	var linkNull sql.NullString
+	var feedNameNull sql.NullString
+	var itunes [5]sql.NullString
+	var fullTextNull sql.NullString
+	var gemtextNull sql.NullString
 
 	r := stmt.QueryRow(ID)
 
-	err := r.Scan(&i.ID, &i.FeedURL, &linkNull, &i.Title, &i.Content, &i.Author, &readAtNull, &i.Favourite, &publishedAtNull, &i.CreatedAt, &i.UpdatedAt)
+	err := r.Scan(&i.ID, &i.FeedURL, &feedNameNull, &linkNull, &i.Title, &i.Content, &i.Author, &readAtNull, &i.Favourite, &publishedAtNull, &i.CreatedAt, &i.UpdatedAt, &itunes[0], &itunes[1], &itunes[2], &itunes[3], &itunes[4], &fullTextNull, &gemtextNull, &i.ReadLater, &i.Note, &i.Score, &i.Cluster)
 	if err != nil {
 		return Item{}, fmt.Errorf("[store.go] GetItemByID: %w", err)
 	}
 
 	i.Link = linkNull.String
+	i.FeedName = feedNameNull.String
+	i.FullText = fullTextNull.String
+	i.Gemtext = gemtextNull.String
 	i.ReadAt = readAtNull.Time
 	i.PublishedAt = publishedAtNull.Time
+	i.ITunes = ITunes{
+		Duration: itunes[0].String,
+		Episode:  itunes[1].String,
+		Season:   itunes[2].String,
+		Image:    itunes[3].String,
+		Subtitle: itunes[4].String,
+	}
+
+	i.Enclosures, err = sls.getEnclosures(i.ID)
+	if err != nil {
+		return Item{}, fmt.Errorf("[store.go] GetItemByID: %w", err)
+	}
+
+	tags, err := sls.getTags(i.ID)
+	if err != nil {
+		return Item{}, fmt.Errorf("[store.go] GetItemByID: %w", err)
+	}
+	i.Categories = tags.categories
+	i.Tags = tags.tags
 
 	return i, nil
 }
@@ -380,3 +1028,270 @@ func (sls SQLiteStore) CountUnread() (int, error) {
 
 	return count, nil
 }
+
+// CountUnreadByFeed returns the number of unread items keyed by feed url
+func (sls SQLiteStore) CountUnreadByFeed() (map[string]int, error) {
+	counts := make(map[string]int)
+
+	rows, err := sls.db.Query(`select feedurl, count(*) from items where readat is null group by feedurl;`)
+	if err != nil {
+		return counts, fmt.Errorf("[store.go] CountUnreadByFeed: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var feedurl string
+		var count int
+
+		err := rows.Scan(&feedurl, &count)
+		if err != nil {
+			return counts, fmt.Errorf("[store.go] CountUnreadByFeed: %w", err)
+		}
+
+		counts[feedurl] = count
+	}
+
+	return counts, nil
+}
+
+// eachID runs query for every ID in one transaction, with the ID as the
+// last argument
+func (sls SQLiteStore) eachID(IDs []int, query string, args ...any) error {
+	tx, err := sls.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.Prepare(query)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	for _, id := range IDs {
+		if _, err := stmt.Exec(append(args, id)...); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SetRead marks items read or unread. Items that are already read keep the
+// time they were read.
+func (sls SQLiteStore) SetRead(IDs []int, read bool) error {
+	var err error
+	if read {
+		err = sls.eachID(IDs, `update items set readat = ? where readat is null and id = ?;`, time.Now())
+	} else {
+		err = sls.eachID(IDs, `update items set readat = null where id = ?;`)
+	}
+	if err != nil {
+		return fmt.Errorf("[store.go] SetRead: %w", err)
+	}
+
+	return nil
+}
+
+func (sls SQLiteStore) SetFavourite(IDs []int, favourite bool) error {
+	err := sls.eachID(IDs, `update items set favourite = ? where id = ?;`, favourite)
+	if err != nil {
+		return fmt.Errorf("[store.go] SetFavourite: %w", err)
+	}
+
+	return nil
+}
+
+// SetTag adds a user tag to items, or removes it
+func (sls SQLiteStore) SetTag(IDs []int, tag string, on bool) error {
+	tag = strings.TrimSpace(tag)
+	if tag == "" {
+		return fmt.Errorf("[store.go] SetTag: empty tag")
+	}
+
+	var err error
+	if on {
+		_, err = sls.db.Exec(`insert or ignore into tags (name) values (?);`, tag)
+		if err != nil {
+			return fmt.Errorf("[store.go] SetTag: %w", err)
+		}
+
+		err = sls.eachID(IDs, `insert or replace into itemtags (itemid, tagid, user) select ?2, id, 1 from tags where name = ?1;`, tag)
+	} else {
+		err = sls.eachID(IDs, `delete from itemtags where user = 1 and tagid in (select id from tags where name = ?1) and itemid = ?2;`, tag)
+	}
+	if err != nil {
+		return fmt.Errorf("[store.go] SetTag: %w", err)
+	}
+
+	return nil
+}
+
+// SetReadAt sets exactly when items were read, keyed by item ID, so changes
+// can be undone. A zero time marks an item unread.
+func (sls SQLiteStore) SetReadAt(readAt map[int]time.Time) error {
+	tx, err := sls.db.Begin()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetReadAt: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`update items set readat = ? where id = ?;`)
+	if err != nil {
+		tx.Rollback()
+		return fmt.Errorf("[store.go] SetReadAt: %w", err)
+	}
+
+	for id, at := range readAt {
+		var value any
+		if !at.IsZero() {
+			value = at
+		}
+
+		if _, err := stmt.Exec(value, id); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("[store.go] SetReadAt: %w", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("[store.go] SetReadAt: %w", err)
+	}
+
+	return nil
+}
+
+// MarkReadWhere marks the unread items that match read, in one go
+func (sls SQLiteStore) MarkReadWhere(match func(Item) bool) error {
+	items, err := sls.getItems(`where readat is null`, "")
+	if err != nil {
+		return fmt.Errorf("[store.go] MarkReadWhere: %w", err)
+	}
+
+	var IDs []int
+	for _, item := range items {
+		if match(item) {
+			IDs = append(IDs, item.ID)
+		}
+	}
+
+	err = sls.eachID(IDs, `update items set readat = ? where id = ?;`, time.Now())
+	if err != nil {
+		return fmt.Errorf("[store.go] MarkReadWhere: %w", err)
+	}
+
+	return nil
+}
+
+// statesChunk is how many IDs are looked up in one query, well under
+// sqlite's limit on variables
+const statesChunk = 500
+
+// GetItemStates returns the read time, favourite and user tags of items, and
+// of the other copies in their clusters, leaving out everything else. nil
+// IDs returns every item.
+func (sls SQLiteStore) GetItemStates(IDs []int) ([]Item, error) {
+	if IDs == nil {
+		items, err := sls.queryStates(`select id, readat, favourite from items;`)
+		if err != nil {
+			return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+		}
+
+		tags, err := sls.getAllTags()
+		if err != nil {
+			return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+		}
+		for i := range items {
+			if t, ok := tags[items[i].ID]; ok {
+				items[i].Tags = t.tags
+			}
+		}
+
+		return items, nil
+	}
+
+	var items []Item
+	seen := make(map[int]bool)
+	for chunk := range slices.Chunk(IDs, statesChunk) {
+		in := placeholders(len(chunk))
+		args := make([]any, 0, len(chunk)*2)
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+		args = append(args, args...)
+
+		found, err := sls.queryStates(`select id, readat, favourite from items where id in (`+in+`) or cluster in (select cluster from items where id in (`+in+`) and cluster is not null);`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+		}
+
+		for _, item := range found {
+			if !seen[item.ID] {
+				seen[item.ID] = true
+				items = append(items, item)
+			}
+		}
+	}
+
+	byID := make(map[int]*Item, len(items))
+	found := make([]int, 0, len(items))
+	for i := range items {
+		byID[items[i].ID] = &items[i]
+		found = append(found, items[i].ID)
+	}
+
+	for chunk := range slices.Chunk(found, statesChunk) {
+		args := make([]any, 0, len(chunk))
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+
+		rows, err := sls.db.Query(`select itemtags.itemid, tags.name from itemtags join tags on tags.id = itemtags.tagid where itemtags.user and itemtags.itemid in (`+placeholders(len(chunk))+`) order by tags.name;`, args...)
+		if err != nil {
+			return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+		}
+
+		for rows.Next() {
+			var id int
+			var name string
+			if err := rows.Scan(&id, &name); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+			}
+			byID[id].Tags = append(byID[id].Tags, name)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("[store.go] GetItemStates: %w", err)
+		}
+	}
+
+	return items, nil
+}
+
+func (sls SQLiteStore) queryStates(query string, args ...any) ([]Item, error) {
+	rows, err := sls.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []Item
+	for rows.Next() {
+		var item Item
+		var readAtNull sql.NullTime
+		if err := rows.Scan(&item.ID, &readAtNull, &item.Favourite); err != nil {
+			return nil, err
+		}
+		item.ReadAt = readAtNull.Time
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
+
+// placeholders is n comma separated query parameters
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
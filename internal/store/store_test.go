@@ -0,0 +1,103 @@
+package store
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+func TestMarkReadWhere(t *testing.T) {
+	s, err := NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	ids := make(map[string]int)
+	for _, title := range []string{"Go one", "Rust two", "Go three"} {
+		id, _, err := s.UpsertItem(Item{FeedURL: "https://example.com/feed", Title: title})
+		test.HandleError(t, err)
+		ids[title] = id
+	}
+	test.HandleError(t, s.ToggleRead(ids["Go three"]))
+	before, err := s.GetItemByID(ids["Go three"])
+	test.HandleError(t, err)
+
+	var seen []string
+	err = s.MarkReadWhere(func(item Item) bool {
+		seen = append(seen, item.Title)
+		return item.Title != "Rust two"
+	})
+	test.HandleError(t, err)
+	test.Equal(t, 2, len(seen), "only unread items are matched")
+
+	for title, id := range ids {
+		item, err := s.GetItemByID(id)
+		test.HandleError(t, err)
+		test.Equal(t, title != "Rust two", item.Read(), title)
+	}
+
+	after, err := s.GetItemByID(ids["Go three"])
+	test.HandleError(t, err)
+	test.Equal(t, true, before.ReadAt.Equal(after.ReadAt), "read time kept")
+}
+
+// readLaterQueue is the IDs in the read later queue, in order
+func readLaterQueue(t *testing.T, s Store) []int {
+	items, err := s.GetReadLaterItems()
+	test.HandleError(t, err)
+
+	var ids []int
+	for _, it := range items {
+		ids = append(ids, it.ID)
+	}
+
+	return ids
+}
+
+func TestReadLaterQueue(t *testing.T) {
+	s, err := NewSQLiteStore(t.TempDir(), "nom.db")
+	test.HandleError(t, err)
+
+	var ids []int
+	for _, title := range []string{"a", "b", "c", "d"} {
+		id, _, err := s.UpsertItem(Item{FeedURL: "https://example.com/feed", Title: title})
+		test.HandleError(t, err)
+		ids = append(ids, id)
+	}
+	a, b, c, d := ids[0], ids[1], ids[2], ids[3]
+
+	for _, id := range []int{a, b, c} {
+		test.HandleError(t, s.AddToReadLater(id))
+	}
+	test.Equal(t, fmt.Sprint([]int{a, b, c}), fmt.Sprint(readLaterQueue(t, s)), "added to the back")
+
+	test.HandleError(t, s.AddToReadLater(a))
+	test.Equal(t, fmt.Sprint([]int{a, b, c}), fmt.Sprint(readLaterQueue(t, s)), "adding again keeps its place")
+
+	test.HandleError(t, s.RemoveFromReadLater(b))
+	test.HandleError(t, s.AddToReadLater(d))
+	test.HandleError(t, s.AddToReadLater(b))
+	test.Equal(t, fmt.Sprint([]int{a, c, d, b}), fmt.Sprint(readLaterQueue(t, s)), "after the last, with gaps")
+
+	test.HandleError(t, s.MoveReadLater(d, -1))
+	test.Equal(t, fmt.Sprint([]int{a, d, c, b}), fmt.Sprint(readLaterQueue(t, s)), "moved up")
+
+	test.HandleError(t, s.MoveReadLater(c, 1))
+	test.Equal(t, fmt.Sprint([]int{a, d, b, c}), fmt.Sprint(readLaterQueue(t, s)), "moved down")
+
+	test.HandleError(t, s.MoveReadLater(b, -10))
+	test.Equal(t, fmt.Sprint([]int{b, a, d, c}), fmt.Sprint(readLaterQueue(t, s)), "kept at the front")
+
+	test.HandleError(t, s.MoveReadLater(a, 10))
+	test.Equal(t, fmt.Sprint([]int{b, d, c, a}), fmt.Sprint(readLaterQueue(t, s)), "kept at the back")
+
+	test.HandleError(t, s.MoveReadLater(b, -1))
+	test.Equal(t, fmt.Sprint([]int{b, d, c, a}), fmt.Sprint(readLaterQueue(t, s)), "first stays first")
+
+	test.HandleError(t, s.AddToReadLater(a))
+	test.HandleError(t, s.RemoveFromReadLater(d))
+	test.HandleError(t, s.AddToReadLater(d))
+	test.Equal(t, fmt.Sprint([]int{b, c, a, d}), fmt.Sprint(readLaterQueue(t, s)), "added after moves")
+
+	err = s.MoveReadLater(99, 1)
+	test.Equal(t, true, err != nil, "item not in the queue")
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package termimg
+
+// CellSize is the default, without a way to ask the terminal
+func CellSize() Cell {
+	return DefaultCell
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package termimg
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// CellSize asks the terminal on stdout how big its cells are
+func CellSize() Cell {
+	ws, err := unix.IoctlGetWinsize(int(os.Stdout.Fd()), unix.TIOCGWINSZ)
+	if err != nil || ws.Xpixel == 0 || ws.Ypixel == 0 || ws.Col == 0 || ws.Row == 0 {
+		return DefaultCell
+	}
+
+	return Cell{Width: int(ws.Xpixel / ws.Col), Height: int(ws.Ypixel / ws.Row)}
+}
@@ -0,0 +1,53 @@
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// halfBlocks draws two pixels a cell, the top one in the foreground of a
+// half block and the bottom one in the background. Transparent pixels are
+// left to the terminal's background.
+func halfBlocks(img *image.NRGBA) []string {
+	b := img.Bounds()
+
+	var rows []string
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		var (
+			row  strings.Builder
+			last string
+		)
+
+		for x := b.Min.X; x < b.Max.X; x++ {
+			top := img.NRGBAAt(x, y)
+			bottom := img.NRGBAAt(x, y+1)
+			if y+1 >= b.Max.Y {
+				bottom.A = 0
+			}
+
+			var style, cell string
+			switch {
+			case top.A < 128 && bottom.A < 128:
+				style, cell = "\x1b[0m", " "
+			case top.A < 128:
+				style, cell = fmt.Sprintf("\x1b[0;38;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B), "▄"
+			case bottom.A < 128:
+				style, cell = fmt.Sprintf("\x1b[0;38;2;%d;%d;%dm", top.R, top.G, top.B), "▀"
+			default:
+				style, cell = fmt.Sprintf("\x1b[0;38;2;%d;%d;%d;48;2;%d;%d;%dm", top.R, top.G, top.B, bottom.R, bottom.G, bottom.B), "▀"
+			}
+
+			if style != last {
+				row.WriteString(style)
+				last = style
+			}
+			row.WriteString(cell)
+		}
+
+		row.WriteString("\x1b[0m")
+		rows = append(rows, row.String())
+	}
+
+	return rows
+}
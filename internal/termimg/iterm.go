@@ -0,0 +1,12 @@
+package termimg
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// iterm is the iTerm2 inline image sequence for a PNG, sized in cells
+func iterm(data []byte, cols, rows int) string {
+	return fmt.Sprintf("\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1;doNotMoveCursor=1:%s\a",
+		len(data), cols, rows, base64.StdEncoding.EncodeToString(data))
+}
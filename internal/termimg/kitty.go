@@ -0,0 +1,73 @@
+package termimg
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// placeholder stands in for a cell of a kitty image, which kitty draws the
+// image over. Text can be scrolled and redrawn as usual with it.
+const placeholder = '\U0010EEEE'
+
+// kittyChunk is the most base64 kitty takes in one escape sequence
+const kittyChunk = 4096
+
+// diacritics number the rows and columns of placeholders, from kitty's
+// rowcolumn-diacritics.txt. Only the first column is numbered, kitty counts
+// on from it along the row.
+var diacritics = []rune{
+	0x0305, 0x030D, 0x030E, 0x0310, 0x0312, 0x033D, 0x033E, 0x033F, 0x0346, 0x034A,
+	0x034B, 0x034C, 0x0350, 0x0351, 0x0352, 0x0357, 0x035B, 0x0363, 0x0364, 0x0365,
+	0x0366, 0x0367, 0x0368, 0x0369, 0x036A, 0x036B, 0x036C, 0x036D, 0x036E, 0x036F,
+	0x0483, 0x0484, 0x0485, 0x0486, 0x0487, 0x0592, 0x0593, 0x0594, 0x0595, 0x0597,
+	0x0598, 0x0599, 0x059C, 0x059D, 0x059E, 0x059F, 0x05A0, 0x05A1, 0x05A8, 0x05A9,
+	0x05AB, 0x05AC, 0x05AF, 0x05C4, 0x0610, 0x0611, 0x0612, 0x0613, 0x0614, 0x0615,
+	0x0616, 0x0617, 0x0657, 0x0658, 0x0659, 0x065A, 0x065B, 0x065D, 0x065E, 0x06D6,
+	0x06D7, 0x06D8, 0x06D9, 0x06DA, 0x06DB, 0x06DC, 0x06DF, 0x06E0, 0x06E1, 0x06E2,
+	0x06E4, 0x06E7, 0x06E8, 0x06EB, 0x06EC,
+}
+
+// MaxRows is the tallest image kitty placeholders can number
+var MaxRows = len(diacritics)
+
+// kittyRows are the placeholders for an image, coloured with its id so
+// kitty knows which image they are
+func kittyRows(id uint32, cols, rows int) []string {
+	lines := make([]string, 0, rows)
+	for r := range min(rows, MaxRows) {
+		var b strings.Builder
+		fmt.Fprintf(&b, "\x1b[38;2;%d;%d;%dm", id>>16&0xff, id>>8&0xff, id&0xff)
+		b.WriteRune(placeholder)
+		b.WriteRune(diacritics[r])
+		b.WriteRune(diacritics[0])
+		b.WriteString(strings.Repeat(string(placeholder), cols-1))
+		b.WriteString("\x1b[39m")
+		lines = append(lines, b.String())
+	}
+
+	return lines
+}
+
+// kittyTransmit sends a PNG to kitty under id, quietly, placed for
+// placeholders cols by rows cells
+func kittyTransmit(data []byte, id uint32, cols, rows int) string {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	var b strings.Builder
+	for i := 0; i < len(payload); i += kittyChunk {
+		chunk := payload[i:min(i+kittyChunk, len(payload))]
+		more := 0
+		if i+kittyChunk < len(payload) {
+			more = 1
+		}
+
+		if i == 0 {
+			fmt.Fprintf(&b, "\x1b_Ga=T,U=1,q=2,f=100,i=%d,c=%d,r=%d,m=%d;%s\x1b\\", id, cols, min(rows, MaxRows), more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+
+	return b.String()
+}
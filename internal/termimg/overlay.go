@@ -0,0 +1,66 @@
+package termimg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	saveCursor    = "\x1b7"
+	restoreCursor = "\x1b8"
+)
+
+// overlay is the rows of an image drawn at the cursor, as iTerm and sixel
+// images are. The last row moves up over the blank rows and draws it there,
+// after the rows above have been written, so they don't wipe it.
+//
+// Each blank row is styled differently, though nothing shows, so a redraw
+// never takes a row that held another image to be unchanged and leaves the
+// old image there.
+func overlay(seq string, id uint32, rows int) []string {
+	lines := make([]string, 0, rows+1)
+	for i := range rows {
+		lines = append(lines, fmt.Sprintf("\x1b[38;2;%d;%d;%dm\x1b[48;5;%dm\x1b[0m", id>>16&0xff, id>>8&0xff, id&0xff, i%256))
+	}
+
+	return append(lines, fmt.Sprintf("%s\x1b[%dA%s%s", saveCursor, rows, seq, restoreCursor))
+}
+
+// OverlayRows is how many rows above line an image drawn from it covers, if
+// it draws one
+func OverlayRows(line string) (int, bool) {
+	i := strings.Index(line, saveCursor+"\x1b[")
+	if i < 0 {
+		return 0, false
+	}
+
+	rest := line[i+len(saveCursor)+2:]
+	end := strings.IndexByte(rest, 'A')
+	if end < 0 {
+		return 0, false
+	}
+
+	rows, err := strconv.Atoi(rest[:end])
+	if err != nil {
+		return 0, false
+	}
+
+	return rows, true
+}
+
+// HideOverlay takes the image out of line, for when the rows it covers
+// aren't all on screen
+func HideOverlay(line string) string {
+	i := strings.Index(line, saveCursor+"\x1b[")
+	if i < 0 {
+		return line
+	}
+
+	end := strings.Index(line[i:], restoreCursor)
+	if end < 0 {
+		return line
+	}
+
+	return line[:i] + line[i+end+len(restoreCursor):]
+}
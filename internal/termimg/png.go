@@ -0,0 +1,17 @@
+package termimg
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+)
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	err := (&png.Encoder{CompressionLevel: png.BestSpeed}).Encode(&buf, img)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,94 @@
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"strings"
+)
+
+// sixel encodes img in a palette of 216 colours, six levels of each of red,
+// green and blue. Transparent pixels are left as they are on screen.
+func sixel(img *image.NRGBA) string {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// the palette index of each pixel, or -1 when it is transparent
+	index := make([]int, w*h)
+	used := make([]bool, 216)
+	for y := range h {
+		for x := range w {
+			c := img.NRGBAAt(b.Min.X+x, b.Min.Y+y)
+			if c.A < 128 {
+				index[y*w+x] = -1
+				continue
+			}
+
+			i := int(c.R)*6/256*36 + int(c.G)*6/256*6 + int(c.B)*6/256
+			index[y*w+x] = i
+			used[i] = true
+		}
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "\x1bP0;1q\"1;1;%d;%d", w, h)
+	for i, ok := range used {
+		if ok {
+			fmt.Fprintf(&s, "#%d;2;%d;%d;%d", i, i/36*20, i/6%6*20, i%6*20)
+		}
+	}
+
+	band := make([]byte, w)
+	for top := 0; top < h; top += 6 {
+		first := true
+		for c, ok := range used {
+			if !ok {
+				continue
+			}
+
+			// each character is six pixels down, one bit for each
+			found := false
+			for x := range w {
+				bits := 0
+				for dy := 0; dy < 6 && top+dy < h; dy++ {
+					if index[(top+dy)*w+x] == c {
+						bits |= 1 << dy
+					}
+				}
+				band[x] = byte(63 + bits)
+				found = found || bits != 0
+			}
+			if !found {
+				continue
+			}
+
+			if !first {
+				s.WriteByte('$')
+			}
+			first = false
+			fmt.Fprintf(&s, "#%d", c)
+			writeRuns(&s, band)
+		}
+		s.WriteByte('-')
+	}
+
+	s.WriteString("\x1b\\")
+
+	return s.String()
+}
+
+// writeRuns writes sixels, repeating runs with !
+func writeRuns(s *strings.Builder, band []byte) {
+	for i := 0; i < len(band); {
+		n := 1
+		for i+n < len(band) && band[i+n] == band[i] {
+			n++
+		}
+
+		if n > 3 {
+			fmt.Fprintf(s, "!%d%c", n, band[i])
+		} else {
+			s.WriteString(strings.Repeat(string(band[i]), n))
+		}
+		i += n
+	}
+}
@@ -0,0 +1,187 @@
+// Package termimg draws images in the terminal, with the kitty graphics
+// protocol, iTerm2 inline images or sixel where the terminal has them, and
+// coloured half blocks where it doesn't.
+package termimg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strings"
+)
+
+type Protocol int
+
+const (
+	None Protocol = iota
+	HalfBlocks
+	Kitty
+	ITerm
+	Sixel
+)
+
+var protocolNames = map[string]Protocol{
+	"halfblocks": HalfBlocks,
+	"kitty":      Kitty,
+	"iterm":      ITerm,
+	"sixel":      Sixel,
+}
+
+func (p Protocol) String() string {
+	for name, q := range protocolNames {
+		if q == p {
+			return name
+		}
+	}
+
+	return "none"
+}
+
+// Parse finds a protocol by name, detecting the terminal's for "auto" or
+// no name
+func Parse(name string, getenv func(string) string) (Protocol, error) {
+	if name == "" || name == "auto" {
+		return Detect(getenv), nil
+	}
+
+	p, ok := protocolNames[name]
+	if !ok {
+		return None, fmt.Errorf("termimg.Parse: unknown protocol %q", name)
+	}
+
+	return p, nil
+}
+
+// Detect guesses the terminal's graphics protocol from its environment.
+// tmux doesn't pass graphics through by default, so gets half blocks.
+func Detect(getenv func(string) string) Protocol {
+	term, program := getenv("TERM"), getenv("TERM_PROGRAM")
+
+	switch {
+	case getenv("TMUX") != "":
+		return HalfBlocks
+	case term == "xterm-kitty" || getenv("KITTY_WINDOW_ID") != "" || program == "ghostty":
+		return Kitty
+	case program == "iTerm.app" || program == "WezTerm":
+		return ITerm
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") || strings.HasPrefix(term, "mlterm"):
+		return Sixel
+	default:
+		return HalfBlocks
+	}
+}
+
+// Cell is the size of a terminal cell in pixels
+type Cell struct {
+	Width  int
+	Height int
+}
+
+// DefaultCell is used when the terminal doesn't say how big its cells are
+var DefaultCell = Cell{Width: 10, Height: 20}
+
+// Options are how big an image may be drawn, and how
+type Options struct {
+	MaxCols int
+	MaxRows int
+	Cell    Cell
+	// ID tells images apart on screen. Kitty keeps the image under it.
+	ID uint32
+}
+
+// Placement is an image ready to put among lines of text, as the Rows it
+// takes up. Kitty images are sent to the terminal once with Transmit and
+// drawn by the text in Rows. iTerm and sixel images are drawn by the last
+// row, over the blank rows above it.
+type Placement struct {
+	Rows     []string
+	Transmit string
+}
+
+// Fit is how many cells an image w by h pixels takes up, shrunk to fit in
+// opts but never grown
+func Fit(w, h int, opts Options) (cols, rows int) {
+	cw, ch := float64(opts.Cell.Width), float64(opts.Cell.Height)
+	scale := min(1, float64(opts.MaxCols)*cw/float64(w), float64(opts.MaxRows)*ch/float64(h))
+
+	cols = max(int(math.Round(float64(w)*scale/cw)), 1)
+	rows = max(int(math.Round(float64(h)*scale/ch)), 1)
+
+	return cols, rows
+}
+
+// Render places img with the protocol p
+func Render(img image.Image, p Protocol, opts Options) (Placement, error) {
+	b := img.Bounds()
+	if b.Empty() {
+		return Placement{}, fmt.Errorf("termimg.Render: empty image")
+	}
+
+	cols, rows := Fit(b.Dx(), b.Dy(), opts)
+
+	switch p {
+	case HalfBlocks:
+		return Placement{Rows: halfBlocks(resize(img, cols, rows*2))}, nil
+
+	case Kitty:
+		data, err := encodePNG(resize(img, cols*opts.Cell.Width, rows*opts.Cell.Height))
+		if err != nil {
+			return Placement{}, fmt.Errorf("termimg.Render: %w", err)
+		}
+		return Placement{Rows: kittyRows(opts.ID, cols, rows), Transmit: kittyTransmit(data, opts.ID, cols, rows)}, nil
+
+	case ITerm:
+		data, err := encodePNG(resize(img, cols*opts.Cell.Width, rows*opts.Cell.Height))
+		if err != nil {
+			return Placement{}, fmt.Errorf("termimg.Render: %w", err)
+		}
+		return Placement{Rows: overlay(iterm(data, cols, rows), opts.ID, rows)}, nil
+
+	case Sixel:
+		return Placement{Rows: overlay(sixel(resize(img, cols*opts.Cell.Width, rows*opts.Cell.Height)), opts.ID, rows)}, nil
+	}
+
+	return Placement{}, fmt.Errorf("termimg.Render: no protocol")
+}
+
+// resize scales img to w by h, averaging the pixels each new one covers
+func resize(img image.Image, w, h int) *image.NRGBA {
+	src := img.Bounds()
+	out := image.NewNRGBA(image.Rect(0, 0, w, h))
+
+	for y := 0; y < h; y++ {
+		y0 := src.Min.Y + y*src.Dy()/h
+		y1 := max(src.Min.Y+(y+1)*src.Dy()/h, y0+1)
+
+		for x := 0; x < w; x++ {
+			x0 := src.Min.X + x*src.Dx()/w
+			x1 := max(src.Min.X+(x+1)*src.Dx()/w, x0+1)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					// weight by alpha so transparent pixels don't darken
+					r += uint64(c.R) * uint64(c.A)
+					g += uint64(c.G) * uint64(c.A)
+					b += uint64(c.B) * uint64(c.A)
+					a += uint64(c.A)
+					n++
+				}
+			}
+
+			if a == 0 {
+				continue
+			}
+			out.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / a >> 8),
+				G: uint8(g / a >> 8),
+				B: uint8(b / a >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return out
+}
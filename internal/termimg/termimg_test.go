@@ -0,0 +1,107 @@
+package termimg
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/guyfedwards/nom/v2/internal/test"
+)
+
+var update = flag.Bool("update", false, "write the golden files from the output")
+
+// testImage is a gradient with a transparent corner
+func testImage() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := range 8 {
+		for x := range 8 {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 32), G: uint8(y * 32), B: 128, A: 255})
+		}
+	}
+	for y := range 3 {
+		for x := range 3 {
+			img.SetNRGBA(x, y, color.NRGBA{})
+		}
+	}
+
+	return img
+}
+
+func TestRenderGolden(t *testing.T) {
+	opts := Options{MaxCols: 3, MaxRows: 4, Cell: Cell{Width: 2, Height: 4}, ID: 0x0a0b0c}
+
+	for _, p := range []Protocol{HalfBlocks, Kitty, ITerm, Sixel} {
+		placement, err := Render(testImage(), p, opts)
+		test.HandleError(t, err)
+
+		have := strings.Join(placement.Rows, "\n") + "\n"
+		if placement.Transmit != "" {
+			have = placement.Transmit + "\n" + have
+		}
+
+		golden := filepath.Join("../test/data/termimg", p.String()+".golden")
+		if *update {
+			test.HandleError(t, os.MkdirAll(filepath.Dir(golden), 0755))
+			test.HandleError(t, os.WriteFile(golden, []byte(have), 0644))
+		}
+
+		want, err := os.ReadFile(golden)
+		test.HandleError(t, err)
+		test.Equal(t, string(want), have, p.String()+" output should match "+golden)
+	}
+}
+
+func TestFit(t *testing.T) {
+	opts := Options{MaxCols: 40, MaxRows: 10, Cell: Cell{Width: 10, Height: 20}}
+
+	cols, rows := Fit(100, 40, opts)
+	test.Equal(t, 10, cols, "small images keep their size")
+	test.Equal(t, 2, rows, "small images keep their size")
+
+	cols, rows = Fit(800, 200, opts)
+	test.Equal(t, 40, cols, "wide images fit the width")
+	test.Equal(t, 5, rows, "wide images keep their shape")
+
+	cols, rows = Fit(200, 1000, opts)
+	test.Equal(t, 4, cols, "tall images keep their shape")
+	test.Equal(t, 10, rows, "tall images fit the height")
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		env  map[string]string
+		want Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, Kitty},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, ITerm},
+		{map[string]string{"TERM": "foot"}, Sixel},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, HalfBlocks},
+		{map[string]string{"TERM": "xterm-256color"}, HalfBlocks},
+	}
+
+	for _, c := range cases {
+		have := Detect(func(k string) string { return c.env[k] })
+		test.Equal(t, c.want, have, "detecting the protocol")
+	}
+
+	_, err := Parse("braille", os.Getenv)
+	test.Equal(t, true, err != nil, "unknown protocols are an error")
+}
+
+func TestOverlay(t *testing.T) {
+	rows := overlay("IMG", 1, 3)
+	test.Equal(t, 4, len(rows), "the image is drawn from a row below it")
+
+	n, ok := OverlayRows("  " + rows[3])
+	test.Equal(t, true, ok, "the row drawing the image is found")
+	test.Equal(t, 3, n, "it covers the rows above")
+
+	test.Equal(t, "  ", HideOverlay("  "+rows[3]), "the image can be left out")
+
+	_, ok = OverlayRows(rows[0])
+	test.Equal(t, false, ok, "blank rows don't draw")
+}